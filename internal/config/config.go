@@ -0,0 +1,405 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/adrg/xdg"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/getnf/winferior/internal/theme"
+	"github.com/getnf/winferior/internal/types"
+)
+
+// Config holds the per-user defaults read from the config file, every key can be
+// overridden by an environment variable named WINFERIOR_<KEY>, e.g. WINFERIOR_INSTALL_DIR or
+// WINFERIOR_THEME_PRESET
+type Config struct {
+	DownloadDir     string   `toml:"download_dir"`
+	InstallDir      string   `toml:"install_dir"`
+	DbDir           string   `toml:"db_dir"`
+	Scope           string   `toml:"scope"`
+	Channel         string   `toml:"channel"`
+	Parallelism     int      `toml:"parallelism"`
+	RefreshInterval string   `toml:"refresh_interval"`
+	KeepTars        bool     `toml:"keep_tars"`
+	ApiUrl          string   `toml:"api_url"`
+	AssetRewrite    string   `toml:"asset_rewrite"`
+	GithubToken     string   `toml:"github_token"`
+	Variants        []string `toml:"variants"`
+	Output          string   `toml:"output"`
+
+	Theme  ThemeConfig  `toml:"theme"`
+	Keymap KeymapConfig `toml:"keymap"`
+}
+
+// ThemeConfig picks a preset theme, the colors and selectors which are set replace the
+// ones of the preset. Colors are ANSI colors like "3" or hex colors like "#f5c2e7".
+type ThemeConfig struct {
+	Preset           string `toml:"preset"`
+	Accent           string `toml:"accent"`
+	Selected         string `toml:"selected"`
+	Danger           string `toml:"danger"`
+	Border           string `toml:"border"`
+	Muted            string `toml:"muted"`
+	Cursor           string `toml:"cursor"`
+	SelectedPrefix   string `toml:"selected_prefix"`
+	UnselectedPrefix string `toml:"unselected_prefix"`
+}
+
+// KeymapConfig picks a preset keymap, the keys of the actions which are set replace the
+// ones of the preset
+type KeymapConfig struct {
+	Preset   string   `toml:"preset"`
+	Up       []string `toml:"up"`
+	Down     []string `toml:"down"`
+	Left     []string `toml:"left"`
+	Right    []string `toml:"right"`
+	PageUp   []string `toml:"page_up"`
+	PageDown []string `toml:"page_down"`
+	Top      []string `toml:"top"`
+	Bottom   []string `toml:"bottom"`
+	Toggle   []string `toml:"toggle"`
+	Filter   []string `toml:"filter"`
+	Submit   []string `toml:"submit"`
+	Quit     []string `toml:"quit"`
+}
+
+const (
+	OutputTable = "table"
+	OutputJson  = "json"
+)
+
+const (
+	KeymapDefault = "default"
+	KeymapVim     = "vim"
+	KeymapEmacs   = "emacs"
+)
+
+var Keymaps = []string{KeymapDefault, KeymapVim, KeymapEmacs}
+
+// refresh intervals checking on every run and only when asked to
+const (
+	IntervalAlways = "always"
+	IntervalNever  = "never"
+)
+
+func Default() Config {
+	return Config{
+		Scope:           string(types.ScopeMachine),
+		Channel:         string(types.ChannelStable),
+		Parallelism:     4,
+		RefreshInterval: "5d",
+		ApiUrl:          types.DefaultApiUrl,
+		Variants:        []string{},
+		Output:          OutputTable,
+		Theme:           ThemeConfig{Preset: theme.PresetWinferior},
+		Keymap:          KeymapConfig{Preset: KeymapDefault},
+	}
+}
+
+func GetConfigPath() string {
+	if path, ok := os.LookupEnv("WINFERIOR_CONFIG"); ok && path != "" {
+		return path
+	}
+	return filepath.Join(xdg.ConfigHome, "WiNFerior", "config.toml")
+}
+
+// LoadFile reads the config file at path on top of the defaults, a missing file is not an error
+func LoadFile(path string) (Config, error) {
+	config := Default()
+
+	_, err := toml.DecodeFile(path, &config)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Default(), fmt.Errorf("error reading config file %v: %v", path, err)
+	}
+
+	return config, nil
+}
+
+// Load reads the config file and applies the environment overrides
+func Load() (Config, error) {
+	config, err := LoadFile(GetConfigPath())
+	if err != nil {
+		return config, err
+	}
+
+	for _, key := range Keys() {
+		value, ok := lookupEnv(key)
+		if !ok {
+			continue
+		}
+		err := config.Set(key, value)
+		if err != nil {
+			return config, fmt.Errorf("%v: %v", EnvName(key), err)
+		}
+	}
+
+	return config, config.Validate()
+}
+
+func (c Config) Save(path string) error {
+	var buffer bytes.Buffer
+	err := toml.NewEncoder(&buffer).Encode(c)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buffer.Bytes(), 0644)
+}
+
+func (c Config) Validate() error {
+	if !types.Scope(c.Scope).IsValid() {
+		return fmt.Errorf("scope must be %v or %v, got %q", types.ScopeMachine, types.ScopeUser, c.Scope)
+	}
+	if !types.Channel(c.Channel).IsValid() {
+		return fmt.Errorf("channel must be %v or %v, got %q", types.ChannelStable, types.ChannelPrerelease, c.Channel)
+	}
+	if c.Parallelism < 1 {
+		return fmt.Errorf("parallelism must be at least 1, got %v", c.Parallelism)
+	}
+	if _, err := ParseInterval(c.RefreshInterval); err != nil {
+		return err
+	}
+	for _, variant := range c.Variants {
+		if !isVariant(variant) {
+			return fmt.Errorf("unknown variant %q, valid variants are %v", variant, strings.Join(types.Variants, ", "))
+		}
+	}
+	if c.Output != OutputTable && c.Output != OutputJson {
+		return fmt.Errorf("output must be %v or %v, got %q", OutputTable, OutputJson, c.Output)
+	}
+	if _, err := theme.Preset(c.Theme.Preset); err != nil {
+		return err
+	}
+	for _, key := range []string{"theme.accent", "theme.selected", "theme.danger", "theme.border", "theme.muted"} {
+		if color, _ := c.Get(key); color != "" && !theme.IsColor(color) {
+			return fmt.Errorf("%v must be an ANSI color or a hex color, got %q", key, color)
+		}
+	}
+	if !slices.Contains(Keymaps, c.Keymap.Preset) {
+		return fmt.Errorf("unknown keymap %q, valid keymaps are %v", c.Keymap.Preset, strings.Join(Keymaps, ", "))
+	}
+	return nil
+}
+
+func isVariant(variant string) bool {
+	for _, v := range types.Variants {
+		if v == variant {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseInterval parses durations like "12h" or "30m" and additionally accepts days, e.g. "5d",
+// "always" and "never". Never is returned as a negative duration.
+func ParseInterval(interval string) (time.Duration, error) {
+	switch interval {
+	case IntervalAlways:
+		return 0, nil
+	case IntervalNever:
+		return -1, nil
+	}
+	if days, found := strings.CutSuffix(interval, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid refresh interval %q", interval)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	duration, err := time.ParseDuration(interval)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid refresh interval %q", interval)
+	}
+	return duration, nil
+}
+
+func (c Config) GetRefreshInterval() time.Duration {
+	interval, _ := ParseInterval(c.RefreshInterval)
+	return interval
+}
+
+func (c Config) Paths() *types.Paths {
+	return types.NewPaths(c.DownloadDir, c.InstallDir, c.DbDir, types.Scope(c.Scope))
+}
+
+func (c Config) Source() *types.Source {
+	return types.NewSource(c.ApiUrl, c.AssetRewrite, c.GithubToken)
+}
+
+func (c Config) Options(paths *types.Paths) types.Options {
+	return types.Options{
+		DownloadPath: paths.GetDownloadPath(),
+		ExtractPath:  paths.GetInstallPath(),
+		KeepTars:     c.KeepTars,
+		Scope:        types.Scope(c.Scope),
+		Channel:      types.Channel(c.Channel),
+		Variants:     c.Variants,
+		Parallelism:  c.Parallelism,
+	}
+}
+
+// GetTheme returns the preset theme with the configured colors and selectors, setting
+// NO_COLOR in the environment always wins over the config
+func (c Config) GetTheme() theme.Theme {
+	t, err := theme.Preset(c.Theme.Preset)
+	if err != nil {
+		t = theme.Winferior()
+	}
+
+	colors := map[*lipgloss.TerminalColor]string{
+		&t.Accent:   c.Theme.Accent,
+		&t.Selected: c.Theme.Selected,
+		&t.Danger:   c.Theme.Danger,
+		&t.Border:   c.Theme.Border,
+		&t.Muted:    c.Theme.Muted,
+	}
+	for color, value := range colors {
+		if value != "" {
+			*color = lipgloss.Color(value)
+		}
+	}
+	selectors := map[*string]string{
+		&t.Cursor:           c.Theme.Cursor,
+		&t.SelectedPrefix:   c.Theme.SelectedPrefix,
+		&t.UnselectedPrefix: c.Theme.UnselectedPrefix,
+	}
+	for selector, value := range selectors {
+		if value != "" {
+			*selector = value
+		}
+	}
+
+	if theme.IsNoColorSet() {
+		noColor := theme.NoColor()
+		noColor.Cursor, noColor.SelectedPrefix, noColor.UnselectedPrefix = t.Cursor, t.SelectedPrefix, t.UnselectedPrefix
+		return noColor
+	}
+	return t
+}
+
+// Keys and their values, the keys of sections are prefixed with the name of the
+// section, e.g. theme.preset
+
+func Keys() []string {
+	var keys []string
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("toml")
+		if t.Field(i).Type.Kind() != reflect.Struct {
+			keys = append(keys, name)
+			continue
+		}
+		for j := 0; j < t.Field(i).Type.NumField(); j++ {
+			keys = append(keys, name+"."+t.Field(i).Type.Field(j).Tag.Get("toml"))
+		}
+	}
+	return keys
+}
+
+func EnvName(key string) string {
+	return "WINFERIOR_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+func lookupEnv(key string) (string, bool) {
+	if value, ok := os.LookupEnv(EnvName(key)); ok && value != "" {
+		return value, true
+	}
+	if key == "github_token" {
+		if value, ok := os.LookupEnv("GITHUB_TOKEN"); ok && value != "" {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// IsFromEnv reports whether the value of key is overridden by the environment
+func IsFromEnv(key string) bool {
+	_, ok := lookupEnv(key)
+	return ok
+}
+
+func (c *Config) field(key string) (reflect.Value, error) {
+	v := reflect.ValueOf(c).Elem()
+	section, name, found := strings.Cut(key, ".")
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).Tag.Get("toml") != section {
+			continue
+		}
+		if !found && v.Field(i).Kind() != reflect.Struct {
+			return v.Field(i), nil
+		}
+		if !found || v.Field(i).Kind() != reflect.Struct {
+			break
+		}
+		for j := 0; j < v.Field(i).NumField(); j++ {
+			if v.Field(i).Type().Field(j).Tag.Get("toml") == name {
+				return v.Field(i).Field(j), nil
+			}
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("unknown config key %q, valid keys are %v", key, strings.Join(Keys(), ", "))
+}
+
+func (c *Config) Get(key string) (string, error) {
+	field, err := c.field(key)
+	if err != nil {
+		return "", err
+	}
+
+	switch field.Kind() {
+	case reflect.Slice:
+		return strings.Join(field.Interface().([]string), ","), nil
+	default:
+		return fmt.Sprint(field.Interface()), nil
+	}
+}
+
+// Set parses value according to the type of key, lists are comma separated
+func (c *Config) Set(key string, value string) error {
+	field, err := c.field(key)
+	if err != nil {
+		return err
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%v must be a number, got %q", key, value)
+		}
+		field.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%v must be true or false, got %q", key, value)
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		list := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		field.Set(reflect.ValueOf(list))
+	}
+
+	return nil
+}
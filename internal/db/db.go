@@ -11,8 +11,12 @@ import (
 	_ "modernc.org/sqlite"
 )
 
+func GetDbFile(path string) string {
+	return path + "/" + "winferior.sqlite3"
+}
+
 func OpenDB(path string) *sql.DB {
-	db, err := sql.Open("sqlite", path+"/"+"winferior.sqlite3")
+	db, err := sql.Open("sqlite", GetDbFile(path))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -37,6 +41,37 @@ func TableIsEmpty(db *sql.DB, table string) bool {
 	}
 }
 
+func hasColumn(db *sql.DB, table string, column string) bool {
+	rows, err := db.Query("SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer rows.Close()
+
+	var name string
+	for rows.Next() {
+		rows.Scan(&name)
+		if name == column {
+			return true
+		}
+	}
+	return false
+}
+
+// addColumnIfMissing adds columns to tables which were created by older versions of winferior
+// and reports whether the column had to be added
+func addColumnIfMissing(db *sql.DB, table string, column string, definition string) bool {
+	if hasColumn(db, table, column) {
+		return false
+	}
+
+	_, err := db.Exec("ALTER TABLE " + table + " ADD COLUMN " + column + " " + definition)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return true
+}
+
 // last checked table for getting the time from now till the last time we check for updated
 
 func CreateLastCheckedTable(db *sql.DB) {
@@ -80,7 +115,7 @@ func GetLastChecked(db *sql.DB) string {
 // Version table
 
 func CreateVersionTable(db *sql.DB) {
-	statement, err := db.Prepare("CREATE TABLE IF NOT EXISTS version (id INTEGER PRIMARY KEY, Version TEXT)")
+	statement, err := db.Prepare("CREATE TABLE IF NOT EXISTS version (id INTEGER PRIMARY KEY, Version TEXT, Channel TEXT DEFAULT 'stable')")
 	if err != nil {
 		log.Fatalln(err)
 		return
@@ -90,26 +125,28 @@ func CreateVersionTable(db *sql.DB) {
 	if err != nil {
 		log.Fatal(err)
 	}
+	addColumnIfMissing(db, "version", "Channel", "TEXT DEFAULT 'stable'")
 }
 
-func InsertIntoVersion(db *sql.DB, version string) {
-	statement, err := db.Prepare("INSERT or REPLACE INTO version (id, Version) VALUES (?, ?)")
+func InsertIntoVersion(db *sql.DB, version string, channel types.Channel) {
+	statement, err := db.Prepare("INSERT or REPLACE INTO version (id, Version, Channel) VALUES ((SELECT id FROM version WHERE Channel=?), ?, ?)")
 	if err != nil {
 		log.Fatalln(err)
 	}
 	defer statement.Close()
 
-	_, err = statement.Exec(1, version)
+	_, err = statement.Exec(channel, version, channel)
 	if err != nil {
 		log.Fatalln(err)
 	}
 }
 
-func GetVersion(db *sql.DB) string {
-	rows, err := db.Query("SELECT Version FROM version")
+func GetVersion(db *sql.DB, channel types.Channel) string {
+	rows, err := db.Query("SELECT Version FROM version WHERE Channel=?", channel)
 	if err != nil {
 		log.Fatalln(err)
 	}
+	defer rows.Close()
 	var version string
 	for rows.Next() {
 		rows.Scan(&version)
@@ -119,8 +156,12 @@ func GetVersion(db *sql.DB) string {
 
 // Fonts table
 
+// the channels can share a release and with it the ids of its assets, the fonts are keyed
+// by both
+const fontsTableColumns = "(Entry INTEGER PRIMARY KEY, Id INTEGER, Name TEXT, ContentType TEXT, BrowserDownloadUrl TEXT, Channel TEXT DEFAULT 'stable', Size INTEGER DEFAULT 0, Digest TEXT DEFAULT '', UNIQUE(Channel, Id))"
+
 func CreateFontsTable(db *sql.DB) {
-	statement, err := db.Prepare("CREATE TABLE IF NOT EXISTS fonts (id INTEGER PRIMARY KEY, Name TEXT, ContentType TEXT, BrowserDownloadUrl TEXT)")
+	statement, err := db.Prepare("CREATE TABLE IF NOT EXISTS fonts " + fontsTableColumns)
 	if err != nil {
 		log.Fatalln(err)
 		return
@@ -130,34 +171,75 @@ func CreateFontsTable(db *sql.DB) {
 	if err != nil {
 		log.Fatal(err)
 	}
+	addColumnIfMissing(db, "fonts", "Channel", "TEXT DEFAULT 'stable'")
+	addedSize := addColumnIfMissing(db, "fonts", "Size", "INTEGER DEFAULT 0")
+	addedDigest := addColumnIfMissing(db, "fonts", "Digest", "TEXT DEFAULT ''")
+	if addedSize || addedDigest {
+		// make the next catalog check unconditional so the new columns get filled
+		db.Exec("DELETE FROM httpCache")
+	}
+	migrateFontsKey(db)
+}
+
+// migrateFontsKey moves the fonts of tables keyed by the asset id alone, in which a channel
+// refreshing a release took over the rows of the other channel, to a table keyed by channel
+// and id
+func migrateFontsKey(db *sql.DB) {
+	if hasColumn(db, "fonts", "Entry") {
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	for _, query := range []string{
+		"ALTER TABLE fonts RENAME TO fontsByAssetId",
+		"CREATE TABLE fonts " + fontsTableColumns,
+		"INSERT INTO fonts (Id, Name, ContentType, BrowserDownloadUrl, Channel, Size, Digest) SELECT id, Name, ContentType, BrowserDownloadUrl, Channel, Size, Digest FROM fontsByAssetId",
+		"DROP TABLE fontsByAssetId",
+	} {
+		_, err = tx.Exec(query)
+		if err != nil {
+			tx.Rollback()
+			log.Fatalln(err)
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// refetch the catalog of the channel which lost its rows
+	db.Exec("DELETE FROM httpCache")
 }
 
-func DeleteFontsTable(db *sql.DB) {
-	statement, err := db.Prepare("DROP TABLE IF EXISTS fonts")
+func DeleteFonts(db *sql.DB, channel types.Channel) {
+	statement, err := db.Prepare("DELETE FROM fonts WHERE Channel=?")
 	if err != nil {
 		log.Fatalln(err)
 		return
 	}
 	defer statement.Close()
-	_, err = statement.Exec()
+	_, err = statement.Exec(channel)
 	if err != nil {
 		log.Fatalln(err)
 	}
 }
 
-func InsertIntoFonts(db *sql.DB, fonts []types.Font) {
+func InsertIntoFonts(db *sql.DB, fonts []types.Font, channel types.Channel) {
 	tx, err := db.Begin()
 	if err != nil {
 		log.Fatal(err)
 	}
-	statement, err := tx.Prepare("INSERT INTO fonts (Id, Name, ContentType, BrowserDownloadUrl) VALUES (?, ?, ?, ?)")
+	statement, err := tx.Prepare("INSERT INTO fonts (Id, Name, ContentType, BrowserDownloadUrl, Channel, Size, Digest) VALUES (?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer statement.Close()
 
 	for _, font := range fonts {
-		_, err = statement.Exec(font.Id, utils.FontNameWithoutExtention(font.Name), font.ContentType, font.BrowserDownloadUrl)
+		_, err = statement.Exec(font.Id, utils.FontNameWithoutExtention(font.Name), font.ContentType, font.BrowserDownloadUrl, channel, font.Size, font.Digest)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -169,38 +251,43 @@ func InsertIntoFonts(db *sql.DB, fonts []types.Font) {
 	}
 }
 
-func GetAllFonts(db *sql.DB) []types.Font {
+func GetAllFonts(db *sql.DB, channel types.Channel) []types.Font {
 	var fonts []types.Font
 	var font types.Font
-	rows, err := db.Query("SELECT Id, Name, ContentType, BrowserDownloadUrl FROM fonts")
+	rows, err := db.Query("SELECT Id, Name, ContentType, BrowserDownloadUrl, Size, Digest FROM fonts WHERE Channel=?", channel)
 	if err != nil {
 		log.Fatalln(err)
 	}
+	defer rows.Close()
 	for rows.Next() {
-		rows.Scan(&font.Id, &font.Name, &font.ContentType, &font.BrowserDownloadUrl)
+		rows.Scan(&font.Id, &font.Name, &font.ContentType, &font.BrowserDownloadUrl, &font.Size, &font.Digest)
+		font.Channel = channel
 		fonts = append(fonts, font)
 	}
 	return fonts
 }
 
-func FontExists(db *sql.DB, font string) bool {
+func HasFonts(db *sql.DB, channel types.Channel) bool {
 	var exists bool
-	err := db.QueryRow("SELECT (Name == ?) From fonts WHERE Name = ?", font, font).Scan(&exists)
-
+	err := db.QueryRow("SELECT EXISTS (SELECT 1 FROM fonts WHERE Channel=?)", channel).Scan(&exists)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return false
-		}
-		return false
+		log.Fatalln(err)
 	}
-
 	return exists
 }
 
+// GetCatalog returns the release stored for channel
+func GetCatalog(db *sql.DB, channel types.Channel) types.NerdFonts {
+	return types.NerdFonts{
+		Version: GetVersion(db, channel),
+		Fonts:   GetAllFonts(db, channel),
+	}
+}
+
 // Installed fonts table
 
 func CreateInstalledFontsTable(db *sql.DB) {
-	statement, err := db.Prepare("CREATE TABLE IF NOT EXISTS installedFonts (Id INTEGER PRIMARY KEY, Name TEXT, Version TEXT)")
+	statement, err := db.Prepare("CREATE TABLE IF NOT EXISTS installedFonts (Id INTEGER PRIMARY KEY, Name TEXT, Version TEXT, Channel TEXT DEFAULT 'stable')")
 	if err != nil {
 		log.Fatalln(err)
 		return
@@ -210,16 +297,28 @@ func CreateInstalledFontsTable(db *sql.DB) {
 	if err != nil {
 		log.Fatal(err)
 	}
+	addColumnIfMissing(db, "installedFonts", "Channel", "TEXT DEFAULT 'stable'")
+	addColumnIfMissing(db, "installedFonts", "InstalledAt", "TEXT DEFAULT ''")
+
+	// reinstalling used to add another row, only the latest one of a font is kept
+	_, err = db.Exec("DELETE FROM installedFonts WHERE Id NOT IN (SELECT MAX(Id) FROM installedFonts GROUP BY Name)")
+	if err != nil {
+		log.Fatal(err)
+	}
+	_, err = db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS installedFontsName ON installedFonts (Name)")
+	if err != nil {
+		log.Fatal(err)
+	}
 }
 
-func InsertIntoInstalledFonts(db *sql.DB, font types.Font, version string) {
-	statement, err := db.Prepare("INSERT INTO installedFonts(Name, Version) VALUES (?, ?)")
+func InsertIntoInstalledFonts(db *sql.DB, font types.Font, version string, channel types.Channel) {
+	statement, err := db.Prepare("INSERT INTO installedFonts(Name, Version, Channel, InstalledAt) VALUES (?, ?, ?, DateTime('now')) ON CONFLICT(Name) DO UPDATE SET Version=excluded.Version, Channel=excluded.Channel, InstalledAt=excluded.InstalledAt")
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer statement.Close()
 
-	_, err = statement.Exec(font.Name, version)
+	_, err = statement.Exec(font.Name, version, channel)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -228,13 +327,13 @@ func InsertIntoInstalledFonts(db *sql.DB, font types.Font, version string) {
 func GetInstalledFonts(db *sql.DB) []types.Font {
 	var fonts []types.Font
 	var font types.Font
-	rows, err := db.Query("SELECT Id, Name, Version FROM installedFonts")
+	rows, err := db.Query("SELECT Id, Name, Version, Channel, InstalledAt FROM installedFonts")
 	if err != nil {
 		log.Fatalln(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
-		rows.Scan(&font.Id, &font.Name, &font.InstalledVersion)
+		rows.Scan(&font.Id, &font.Name, &font.InstalledVersion, &font.Channel, &font.InstalledAt)
 		fonts = append(fonts, font)
 	}
 	sort.Slice(fonts, func(i, j int) bool { return strings.ToLower(fonts[i].Name) < strings.ToLower(fonts[j].Name) })
@@ -257,7 +356,7 @@ func IsFontInstalled(db *sql.DB, font string) bool {
 
 func GetInstalledFont(db *sql.DB, font types.Font) types.Font {
 	var installedFont types.Font
-	err := db.QueryRow("SELECT Id, Name, Version FROM installedFonts WHERE Name=?", font.Name).Scan(&installedFont.Id, &installedFont.Name, &installedFont.InstalledVersion)
+	err := db.QueryRow("SELECT Id, Name, Version, Channel, InstalledAt FROM installedFonts WHERE Name=?", font.Name).Scan(&installedFont.Id, &installedFont.Name, &installedFont.InstalledVersion, &installedFont.Channel, &installedFont.InstalledAt)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -269,18 +368,376 @@ func GetInstalledFont(db *sql.DB, font types.Font) types.Font {
 	return installedFont
 }
 
-func UpdateInstalledFont(db *sql.DB, name string, version string) {
-	statement, err := db.Prepare("UPDATE installedFonts SET Version=? WHERE Name=?")
+func DeleteInstalledFont(db *sql.DB, name string) {
+	statement, err := db.Prepare("DELETE FROM installedFonts WHERE Name=?")
 	if err != nil {
 		log.Fatalln(err)
 	}
-	statement.Exec(version, name)
+	statement.Exec(name)
 }
 
-func DeleteInstalledFont(db *sql.DB, name string) {
-	statement, err := db.Prepare("DELETE FROM installedFonts WHERE Name=?")
+// Http cache table
+
+func CreateHttpCacheTable(db *sql.DB) {
+	statement, err := db.Prepare("CREATE TABLE IF NOT EXISTS httpCache (Url TEXT PRIMARY KEY, ETag TEXT, LastModified TEXT)")
 	if err != nil {
 		log.Fatalln(err)
+		return
 	}
-	statement.Exec(name)
+	defer statement.Close()
+	_, err = statement.Exec()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func GetHttpCache(db *sql.DB, url string) types.HttpCache {
+	cache := types.HttpCache{Url: url}
+	err := db.QueryRow("SELECT ETag, LastModified FROM httpCache WHERE Url=?", url).Scan(&cache.ETag, &cache.LastModified)
+
+	if err != nil && err != sql.ErrNoRows {
+		log.Fatalln(err)
+	}
+
+	return cache
+}
+
+func UpdateHttpCache(db *sql.DB, cache types.HttpCache) {
+	statement, err := db.Prepare("INSERT or REPLACE INTO httpCache (Url, ETag, LastModified) VALUES (?, ?, ?)")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer statement.Close()
+
+	_, err = statement.Exec(cache.Url, cache.ETag, cache.LastModified)
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func GetInstalledChannels(db *sql.DB) []types.Channel {
+	var channels []types.Channel
+	var channel types.Channel
+	rows, err := db.Query("SELECT DISTINCT Channel FROM installedFonts")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		rows.Scan(&channel)
+		channels = append(channels, channel)
+	}
+	return channels
+}
+
+// Releases table, caches release notes for offline viewing
+
+func CreateReleasesTable(db *sql.DB) {
+	statement, err := db.Prepare("CREATE TABLE IF NOT EXISTS releases (Tag TEXT PRIMARY KEY, Name TEXT, Body TEXT, PublishedAt TEXT, Prerelease INTEGER)")
+	if err != nil {
+		log.Fatalln(err)
+		return
+	}
+	defer statement.Close()
+	_, err = statement.Exec()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func InsertIntoReleases(db *sql.DB, releases []types.NerdFonts) {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Fatal(err)
+	}
+	statement, err := tx.Prepare("INSERT or REPLACE INTO releases (Tag, Name, Body, PublishedAt, Prerelease) VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer statement.Close()
+
+	for _, release := range releases {
+		_, err = statement.Exec(release.Version, release.Name, release.Body, release.PublishedAt, release.Prerelease)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func GetReleases(db *sql.DB) []types.NerdFonts {
+	var releases []types.NerdFonts
+	var release types.NerdFonts
+	rows, err := db.Query("SELECT Tag, Name, Body, PublishedAt, Prerelease FROM releases")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		rows.Scan(&release.Version, &release.Name, &release.Body, &release.PublishedAt, &release.Prerelease)
+		releases = append(releases, release)
+	}
+	return releases
+}
+
+// Font metadata table
+
+func CreateFontMetaTable(db *sql.DB) {
+	statement, err := db.Prepare("CREATE TABLE IF NOT EXISTS fontMeta (Name TEXT PRIMARY KEY, Ligatures INTEGER DEFAULT 0, Monospace INTEGER DEFAULT 1, Family TEXT DEFAULT '', License TEXT DEFAULT '', ArchiveLicense TEXT DEFAULT '', Description TEXT DEFAULT '', Version TEXT DEFAULT '')")
+	if err != nil {
+		log.Fatalln(err)
+		return
+	}
+	defer statement.Close()
+	_, err = statement.Exec()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, column := range []string{"Family", "License", "ArchiveLicense", "Description", "Version"} {
+		addColumnIfMissing(db, "fontMeta", column, "TEXT DEFAULT ''")
+	}
+}
+
+// SeedFontMeta adds the metadata of fonts which have none yet, existing rows are kept
+func SeedFontMeta(db *sql.DB, metas []types.FontMeta) {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	statement, err := tx.Prepare("INSERT INTO fontMeta(Name, Ligatures, Monospace) VALUES (?, ?, ?) ON CONFLICT(Name) DO NOTHING")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer statement.Close()
+
+	for _, meta := range metas {
+		_, err = statement.Exec(meta.Name, meta.Ligatures, meta.Monospace)
+		if err != nil {
+			tx.Rollback()
+			log.Fatalln(err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// ReplaceFontMeta stores the metadata of the fonts.json of release version, the licenses
+// detected in archives are kept
+func ReplaceFontMeta(db *sql.DB, metas []types.FontMeta, version string) {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	statement, err := tx.Prepare("INSERT INTO fontMeta(Name, Ligatures, Monospace, Family, License, Description, Version) VALUES (?, ?, ?, ?, ?, ?, ?) ON CONFLICT(Name) DO UPDATE SET Ligatures=excluded.Ligatures, Monospace=excluded.Monospace, Family=excluded.Family, License=excluded.License, Description=excluded.Description, Version=excluded.Version")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer statement.Close()
+
+	for _, meta := range metas {
+		_, err = statement.Exec(meta.Name, meta.Ligatures, meta.Monospace, meta.Family, meta.License, meta.Description, version)
+		if err != nil {
+			tx.Rollback()
+			log.Fatalln(err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func UpdateArchiveLicense(db *sql.DB, name string, license string) {
+	statement, err := db.Prepare("UPDATE fontMeta SET ArchiveLicense=? WHERE Name=?")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer statement.Close()
+	statement.Exec(license, name)
+}
+
+func GetFontMeta(db *sql.DB) map[string]types.FontMeta {
+	metas := make(map[string]types.FontMeta)
+	rows, err := db.Query("SELECT Name, Ligatures, Monospace, Family, License, ArchiveLicense, Description FROM fontMeta")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var meta types.FontMeta
+		rows.Scan(&meta.Name, &meta.Ligatures, &meta.Monospace, &meta.Family, &meta.License, &meta.ArchiveLicense, &meta.Description)
+		metas[meta.Name] = meta
+	}
+	return metas
+}
+
+// GetFontMetaVersion returns the release the stored fonts.json belongs to, empty when only
+// the builtin metadata is stored
+func GetFontMetaVersion(db *sql.DB) string {
+	var version string
+	err := db.QueryRow("SELECT Version FROM fontMeta WHERE Version != '' LIMIT 1").Scan(&version)
+	if err != nil && err != sql.ErrNoRows {
+		log.Fatalln(err)
+	}
+	return version
+}
+
+// Font aliases table
+
+func CreateFontAliasesTable(db *sql.DB) {
+	statement, err := db.Prepare("CREATE TABLE IF NOT EXISTS fontAliases (Alias TEXT PRIMARY KEY, Name TEXT)")
+	if err != nil {
+		log.Fatalln(err)
+		return
+	}
+	defer statement.Close()
+	_, err = statement.Exec()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// SeedFontAliases adds the aliases which are not stored yet, existing rows are kept
+func SeedFontAliases(db *sql.DB, aliases []types.FontAlias) {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	statement, err := tx.Prepare("INSERT INTO fontAliases(Alias, Name) VALUES (?, ?) ON CONFLICT(Alias) DO NOTHING")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer statement.Close()
+
+	for _, alias := range aliases {
+		_, err = statement.Exec(alias.Alias, alias.Name)
+		if err != nil {
+			tx.Rollback()
+			log.Fatalln(err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// InsertFontAliases stores aliases, an alias which is already stored is pointed at the
+// font it is given for now
+func InsertFontAliases(db *sql.DB, aliases []types.FontAlias) {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	statement, err := tx.Prepare("INSERT INTO fontAliases(Alias, Name) VALUES (?, ?) ON CONFLICT(Alias) DO UPDATE SET Name=excluded.Name")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer statement.Close()
+
+	for _, alias := range aliases {
+		_, err = statement.Exec(alias.Alias, alias.Name)
+		if err != nil {
+			tx.Rollback()
+			log.Fatalln(err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func GetFontAliases(db *sql.DB) []types.FontAlias {
+	var aliases []types.FontAlias
+	rows, err := db.Query("SELECT Alias, Name FROM fontAliases")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var alias types.FontAlias
+		rows.Scan(&alias.Alias, &alias.Name)
+		aliases = append(aliases, alias)
+	}
+	return aliases
+}
+
+// Glyphs table, caches the glyph names of a release
+
+func CreateGlyphsTable(db *sql.DB) {
+	statement, err := db.Prepare("CREATE TABLE IF NOT EXISTS glyphs (Name TEXT PRIMARY KEY, Code TEXT, Char TEXT, Version TEXT)")
+	if err != nil {
+		log.Fatalln(err)
+		return
+	}
+	defer statement.Close()
+	_, err = statement.Exec()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// ReplaceGlyphs replaces the cached glyphs with the glyphs of release version
+func ReplaceGlyphs(db *sql.DB, glyphs []types.Glyph, version string) {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Fatal(err)
+	}
+	_, err = tx.Exec("DELETE FROM glyphs")
+	if err != nil {
+		log.Fatal(err)
+	}
+	statement, err := tx.Prepare("INSERT INTO glyphs (Name, Code, Char, Version) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer statement.Close()
+
+	for _, glyph := range glyphs {
+		_, err = statement.Exec(glyph.Name, glyph.Code, glyph.Char, version)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func GetGlyphs(db *sql.DB) []types.Glyph {
+	var glyphs []types.Glyph
+	var glyph types.Glyph
+	rows, err := db.Query("SELECT Name, Code, Char FROM glyphs ORDER BY Name")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		rows.Scan(&glyph.Name, &glyph.Code, &glyph.Char)
+		glyphs = append(glyphs, glyph)
+	}
+	return glyphs
+}
+
+// GetGlyphsVersion returns the release the cached glyphs belong to
+func GetGlyphsVersion(db *sql.DB) string {
+	var version string
+	err := db.QueryRow("SELECT Version FROM glyphs LIMIT 1").Scan(&version)
+	if err != nil && err != sql.ErrNoRows {
+		log.Fatalln(err)
+	}
+	return version
 }
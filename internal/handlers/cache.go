@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/getnf/winferior/internal/config"
+	"github.com/getnf/winferior/internal/db"
+	"github.com/getnf/winferior/internal/types"
+)
+
+// verifyArchive checks an archive against the sha256 digest of the catalog, catalogs
+// without digests only allow checking the size
+func verifyArchive(path string, font types.Font) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if font.Size > 0 && info.Size() != font.Size {
+		return fmt.Errorf("%v has %v bytes instead of %v", filepath.Base(path), info.Size(), font.Size)
+	}
+
+	algorithm, expected, found := strings.Cut(font.Digest, ":")
+	if !found || algorithm != "sha256" {
+		if font.Size == 0 {
+			return fmt.Errorf("%v can not be verified", filepath.Base(path))
+		}
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return err
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != expected {
+		return fmt.Errorf("%v has the checksum %v instead of %v", filepath.Base(path), actual, expected)
+	}
+
+	return nil
+}
+
+// knownFontNames returns the names of the fonts in the catalogs and of the installed fonts
+func knownFontNames(database *sql.DB) map[string]bool {
+	known := make(map[string]bool)
+	for _, channel := range []types.Channel{types.ChannelStable, types.ChannelPrerelease} {
+		for _, font := range db.GetAllFonts(database, channel) {
+			known[font.Name] = true
+		}
+	}
+	for _, font := range db.GetInstalledFonts(database) {
+		known[font.Name] = true
+	}
+	return known
+}
+
+// ListCache returns the archives of fonts in the download directory, oldest first. The
+// download directory can be shared with other files, archives which are not named after
+// a font of the catalogs or an installed font are left out.
+func ListCache(database *sql.DB, downloadPath string) []types.CacheEntry {
+	var entries []types.CacheEntry
+
+	files, err := os.ReadDir(downloadPath)
+	if err != nil {
+		return entries
+	}
+
+	known := knownFontNames(database)
+	for _, file := range files {
+		name, version, ok := types.ParseArchiveName(file.Name())
+		if file.IsDir() || !ok || !known[name] {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		entry := types.CacheEntry{
+			Name:    name,
+			Version: version,
+			Path:    filepath.Join(downloadPath, file.Name()),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		}
+		if db.IsFontInstalled(database, name) {
+			entry.InstalledVersion = db.GetInstalledFont(database, types.Font{Name: name}).InstalledVersion
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ModTime.Before(entries[j].ModTime) })
+	return entries
+}
+
+func cacheAction(entry types.CacheEntry) types.Action {
+	return types.Action{Kind: types.ActionDeleteArchive, Path: entry.Path, Size: entry.Size}
+}
+
+// PlanPrune plans removing the archives older than olderThan, the archives of versions
+// which are not installed when notInstalled is set, and then the oldest archives until
+// the cache fits into maxSize. Zero values disable the respective rule.
+func PlanPrune(entries []types.CacheEntry, olderThan time.Duration, maxSize int64, notInstalled bool) types.Plan {
+	var plan types.Plan
+	var keep []types.CacheEntry
+
+	for _, entry := range entries {
+		isOld := olderThan > 0 && time.Since(entry.ModTime) > olderThan
+		if isOld || (notInstalled && !entry.IsInstalled()) {
+			plan.Add(cacheAction(entry))
+		} else {
+			keep = append(keep, entry)
+		}
+	}
+
+	if maxSize > 0 {
+		var size int64
+		for _, entry := range keep {
+			size += entry.Size
+		}
+		for _, entry := range keep {
+			if size <= maxSize {
+				break
+			}
+			plan.Add(cacheAction(entry))
+			size -= entry.Size
+		}
+	}
+
+	return plan
+}
+
+func printCache(entries []types.CacheEntry) {
+	if len(entries) == 0 {
+		fmt.Println("The cache is empty")
+		return
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 4, '\t', tabwriter.AlignRight)
+	fmt.Fprintln(writer, "Name:\tVersion:\tSize:\tDownloaded:\tInstalled:")
+
+	var size int64
+	for _, entry := range entries {
+		version := entry.Version
+		if version == "" {
+			version = "-"
+		}
+		installed := "no"
+		if entry.IsInstalled() {
+			installed = "yes"
+		} else if entry.InstalledVersion != "" {
+			installed = entry.InstalledVersion
+		}
+		fmt.Fprintln(writer, entry.Name, "\t", version, "\t", humanize.Bytes(uint64(entry.Size)), "\t", humanize.Time(entry.ModTime), "\t", installed)
+		size += entry.Size
+	}
+	writer.Flush()
+
+	fmt.Printf("%v archive(s), %v in total\n", len(entries), humanize.Bytes(uint64(size)))
+}
+
+func HandleCache(cmd *types.CacheCmd, database *sql.DB, opts types.Options) error {
+	entries := ListCache(database, opts.DownloadPath)
+
+	var plan types.Plan
+	switch {
+	case cmd.Clean != nil:
+		for _, entry := range entries {
+			plan.Add(cacheAction(entry))
+		}
+	case cmd.Prune != nil:
+		var olderThan time.Duration
+		var maxSize uint64
+		var err error
+		if cmd.Prune.OlderThan != "" {
+			olderThan, err = config.ParseInterval(cmd.Prune.OlderThan)
+			if err != nil || olderThan <= 0 {
+				return fmt.Errorf("invalid age %q", cmd.Prune.OlderThan)
+			}
+		}
+		if cmd.Prune.MaxSize != "" {
+			maxSize, err = humanize.ParseBytes(cmd.Prune.MaxSize)
+			if err != nil {
+				return fmt.Errorf("invalid size %q: %v", cmd.Prune.MaxSize, err)
+			}
+		}
+		notInstalled := cmd.Prune.NotInstalled || (olderThan == 0 && maxSize == 0)
+		plan = PlanPrune(entries, olderThan, int64(maxSize), notInstalled)
+	default:
+		printCache(entries)
+		return nil
+	}
+
+	if plan.IsEmpty() {
+		fmt.Println("Nothing to remove from the cache")
+		return nil
+	}
+	if opts.DryRun {
+		PrintPlan(plan, opts)
+		return nil
+	}
+
+	_, err := ExecutePlan(database, plan, opts)
+	fmt.Printf("Removed %v archive(s), freeing %v\n", len(plan.Actions), humanize.Bytes(uint64(plan.GetSize(types.ActionDeleteArchive))))
+
+	return err
+}
+
+// IsCached reports whether the archive of font from release version is in the cache, only
+// its size is checked to keep this cheap
+func IsCached(font types.Font, version string, opts types.Options) bool {
+	info, err := os.Stat(archivePath(opts.DownloadPath, font.Name, version))
+	return err == nil && (font.Size == 0 || info.Size() == font.Size)
+}
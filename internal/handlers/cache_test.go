@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/getnf/winferior/internal/types"
+)
+
+func TestVerifyArchive(t *testing.T) {
+	content := []byte("archive")
+	sum := sha256.Sum256(content)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	path := filepath.Join(t.TempDir(), "Hack-v3.2.1.tar.xz")
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		font    types.Font
+		wantErr bool
+	}{
+		{"matching size and digest", types.Font{Size: 7, Digest: digest}, false},
+		{"matching digest", types.Font{Digest: digest}, false},
+		{"matching size without digest", types.Font{Size: 7}, false},
+		{"digest mismatch", types.Font{Size: 7, Digest: "sha256:" + hex.EncodeToString(make([]byte, 32))}, true},
+		{"size mismatch", types.Font{Size: 8, Digest: digest}, true},
+		{"nothing to verify", types.Font{}, true},
+		{"unknown algorithm", types.Font{Digest: "md5:0"}, true},
+	}
+	for _, tt := range tests {
+		err := verifyArchive(path, tt.font)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%v: verifyArchive returned %v, want an error: %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestPlanPrune(t *testing.T) {
+	now := time.Now()
+	// sorted oldest first like ListCache returns them
+	entries := []types.CacheEntry{
+		{Name: "Hack", Version: "v3.1.1", Path: "Hack-v3.1.1.tar.xz", Size: 40, ModTime: now.Add(-60 * 24 * time.Hour), InstalledVersion: "v3.2.1"},
+		{Name: "Meslo", Version: "", Path: "Meslo.tar.xz", Size: 30, ModTime: now.Add(-40 * 24 * time.Hour)},
+		{Name: "Hack", Version: "v3.2.1", Path: "Hack-v3.2.1.tar.xz", Size: 20, ModTime: now.Add(-20 * 24 * time.Hour), InstalledVersion: "v3.2.1"},
+		{Name: "FiraCode", Version: "v3.2.1", Path: "FiraCode-v3.2.1.tar.xz", Size: 10, ModTime: now.Add(-time.Hour)},
+	}
+
+	tests := []struct {
+		name         string
+		olderThan    time.Duration
+		maxSize      int64
+		notInstalled bool
+		want         []string
+	}{
+		{name: "nothing", want: nil},
+		{name: "not installed keeps the installed version", notInstalled: true, want: []string{"Hack-v3.1.1.tar.xz", "Meslo.tar.xz", "FiraCode-v3.2.1.tar.xz"}},
+		{name: "older than", olderThan: 30 * 24 * time.Hour, want: []string{"Hack-v3.1.1.tar.xz", "Meslo.tar.xz"}},
+		{name: "max size removes the oldest", maxSize: 35, want: []string{"Hack-v3.1.1.tar.xz", "Meslo.tar.xz"}},
+		{name: "max size after not installed", maxSize: 20, notInstalled: true, want: []string{"Hack-v3.1.1.tar.xz", "Meslo.tar.xz", "FiraCode-v3.2.1.tar.xz"}},
+	}
+	for _, tt := range tests {
+		plan := PlanPrune(entries, tt.olderThan, tt.maxSize, tt.notInstalled)
+		var got []string
+		for _, action := range plan.GetActions(types.ActionDeleteArchive) {
+			got = append(got, action.Path)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%v: PlanPrune deletes %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
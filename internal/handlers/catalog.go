@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/getnf/winferior/internal/db"
+	"github.com/getnf/winferior/internal/types"
+	"github.com/getnf/winferior/internal/utils"
+)
+
+var ErrNotModified = errors.New("the release catalog has not been modified")
+
+func SetupDB(database *sql.DB, remoteData types.NerdFonts, channel types.Channel) {
+	db.CreateVersionTable(database)
+	db.CreateFontsTable(database)
+	db.CreateInstalledFontsTable(database)
+	db.CreateFontAliasesTable(database)
+	db.SeedFontAliases(database, types.BuiltinFontAliases())
+
+	if remoteData.GetVersion() == "" || len(remoteData.GetFonts()) == 0 {
+		return
+	}
+
+	localVersion := db.GetVersion(database, channel)
+	isNewRelease := localVersion == "" || IsUpdateAvilable(remoteData.GetVersion(), localVersion)
+
+	if isNewRelease {
+		db.InsertIntoVersion(database, remoteData.GetVersion(), channel)
+		fmt.Printf("Updated %v fonts version\n", channel)
+	}
+
+	// remoteData only reaches here when the release changed on the remote, its assets are
+	// stored even for the same version to pick up re-uploaded archives
+	db.DeleteFonts(database, channel)
+	db.InsertIntoFonts(database, remoteData.GetFonts(), channel)
+	if isNewRelease {
+		fmt.Println("Updating local fonts db")
+	}
+}
+
+// RefreshCatalog fetches the releases of channels and the font metadata of the release of
+// the first channel when the last check is older than interval or force is set, a negative
+// interval never checks on its own. The requests are conditional on the validators of the
+// previous responses, an unchanged catalog costs a 304 which does not count against the
+// GitHub rate limit.
+func RefreshCatalog(database *sql.DB, source *types.Source, channels []types.Channel, interval time.Duration, force bool) error {
+	db.CreateLastCheckedTable(database)
+	db.CreateHttpCacheTable(database)
+	SetupDB(database, types.NerdFonts{}, types.ChannelStable)
+
+	hasCatalog := true
+	for _, channel := range channels {
+		hasCatalog = hasCatalog && db.HasFonts(database, channel)
+	}
+
+	if hasCatalog && !force && interval < 0 {
+		return nil
+	}
+
+	if hasCatalog && !force && !db.TableIsEmpty(database, "lastChecked") {
+		lastChecked, _ := time.Parse(time.DateTime, db.GetLastChecked(database))
+		if time.Since(lastChecked) < interval {
+			return nil
+		}
+	}
+
+	for _, channel := range channels {
+		err := refreshChannel(database, source, channel)
+		if err != nil {
+			return err
+		}
+	}
+	db.UpdateLastChecked(database)
+
+	// the catalog is usable without the metadata of Nerd Fonts, until the next refresh the
+	// builtin metadata is shown
+	err := refreshFontMeta(database, source, db.GetVersion(database, channels[0]))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	return nil
+}
+
+func refreshChannel(database *sql.DB, source *types.Source, channel types.Channel) error {
+	url := source.GetLatestReleaseUrl()
+	if channel == types.ChannelPrerelease {
+		url = source.GetReleasesUrl()
+	}
+
+	cache := db.GetHttpCache(database, url)
+	if !db.HasFonts(database, channel) {
+		// without a local catalog a 304 would leave us with nothing
+		cache.ETag = ""
+		cache.LastModified = ""
+	}
+
+	var remoteData types.NerdFonts
+	var err error
+	if channel == types.ChannelPrerelease {
+		var releases []types.NerdFonts
+		releases, err = GetReleases(source, &cache)
+		if err == nil {
+			var found bool
+			remoteData, found = channel.LatestRelease(releases)
+			if !found {
+				// keep the validators so the next refresh fetches the releases again
+				return fmt.Errorf("no %v release found at %v", channel, source.GetApiUrl())
+			}
+		}
+	} else {
+		remoteData, err = GetData(source, &cache)
+	}
+	if errors.Is(err, ErrNotModified) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	SetupDB(database, remoteData, channel)
+	db.UpdateHttpCache(database, cache)
+
+	return nil
+}
+
+// GetData fetches the latest release, when cache holds validators the request is made
+// conditional and ErrNotModified is returned if the release did not change. On success
+// cache is updated with the validators of the response.
+func GetData(source *types.Source, cache *types.HttpCache) (types.NerdFonts, error) {
+	var data types.NerdFonts
+	err := getJson(source, source.GetLatestReleaseUrl(), cache, &data)
+	if err != nil {
+		return types.NerdFonts{}, err
+	}
+
+	// never hand out a catalog that would replace the local one with nothing
+	if data.GetVersion() == "" || len(data.GetFonts()) == 0 {
+		return types.NerdFonts{}, fmt.Errorf("the release catalog returned by %v is empty", source.GetApiUrl())
+	}
+
+	return data, nil
+}
+
+// GetReleases fetches the most recent releases including prereleases, see GetData for cache
+func GetReleases(source *types.Source, cache *types.HttpCache) ([]types.NerdFonts, error) {
+	var releases []types.NerdFonts
+	err := getJson(source, source.GetReleasesUrl(), cache, &releases)
+	if err != nil {
+		return nil, err
+	}
+
+	releases = utils.Filter(releases, func(r types.NerdFonts) bool { return r.GetVersion() != "" && len(r.GetFonts()) > 0 })
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("the release catalog returned by %v is empty", source.GetApiUrl())
+	}
+
+	return releases, nil
+}
+
+func getJson(source *types.Source, url string, cache *types.HttpCache, v any) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := source.GetToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if cache != nil {
+		if cache.ETag != "" {
+			req.Header.Set("If-None-Match", cache.ETag)
+		}
+		if cache.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cache.LastModified)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return ErrNotModified
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	err = checkApiResponse(resp, body)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(body, v)
+	if err != nil {
+		return fmt.Errorf("error decoding the release catalog: %v", err)
+	}
+
+	if cache != nil {
+		cache.Url = url
+		cache.ETag = resp.Header.Get("ETag")
+		cache.LastModified = resp.Header.Get("Last-Modified")
+	}
+
+	return nil
+}
+
+// checkApiResponse turns error responses of the GitHub API into errors, rate limiting
+// errors include the time at which the limit resets
+func checkApiResponse(resp *http.Response, body []byte) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var apiError struct {
+		Message string `json:"message"`
+	}
+	json.Unmarshal(body, &apiError)
+	if apiError.Message == "" {
+		apiError.Message = resp.Status
+	}
+
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+			if err == nil {
+				return fmt.Errorf("GitHub API rate limit exceeded, it resets at %v (set GITHUB_TOKEN to raise the limit)", time.Unix(reset, 0).Format(time.DateTime))
+			}
+			return fmt.Errorf("GitHub API rate limit exceeded (set GITHUB_TOKEN to raise the limit)")
+		}
+		if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+			return fmt.Errorf("GitHub API rate limit exceeded, retry after %v seconds", retryAfter)
+		}
+	}
+
+	return fmt.Errorf("GitHub API returned %v: %v", resp.StatusCode, apiError.Message)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getnf/winferior/internal/types"
+)
+
+const upstreamDownloadUrl = "https://github.com/ryanoasis/nerd-fonts/releases/download"
+
+// newMirror serves a release at /api/releases/latest whose assets point at GitHub, and the
+// archives at /assets like a mirror of both would
+func newMirror(t *testing.T, requests map[string]http.Header) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path] = r.Header.Clone()
+		if r.Header.Get("If-None-Match") == `"v3.2.1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v3.2.1"`)
+		fmt.Fprintf(w, `{
+			"tag_name": "v3.2.1",
+			"assets": [
+				{"id": 1, "name": "Hack.tar.xz", "content_type": "application/x-xz", "browser_download_url": "%[1]v/v3.2.1/Hack.tar.xz", "size": 7},
+				{"id": 2, "name": "Hack.zip", "content_type": "application/zip", "browser_download_url": "%[1]v/v3.2.1/Hack.zip", "size": 7}
+			]
+		}`, upstreamDownloadUrl)
+	})
+	mux.HandleFunc("/assets/", func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path] = r.Header.Clone()
+		fmt.Fprint(w, "archive")
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMirrorSource(t *testing.T) {
+	requests := make(map[string]http.Header)
+	server := newMirror(t, requests)
+	source := types.NewSource(server.URL+"/api/", upstreamDownloadUrl+"="+server.URL+"/assets", "github-token")
+
+	var cache types.HttpCache
+	data, err := GetData(source, &cache)
+	if err != nil {
+		t.Fatalf("GetData returned %v", err)
+	}
+	header, ok := requests["/api/releases/latest"]
+	if !ok {
+		t.Fatalf("the release was not fetched from the custom api url, requests: %v", requests)
+	}
+	if auth := header.Get("Authorization"); auth != "" {
+		t.Errorf("the GitHub token was sent to the mirror: %q", auth)
+	}
+	if data.GetVersion() != "v3.2.1" || len(data.GetFonts()) != 1 {
+		t.Fatalf("GetData = %v with %d archives, want v3.2.1 with 1", data.GetVersion(), len(data.GetFonts()))
+	}
+	if cache.ETag != `"v3.2.1"` {
+		t.Errorf("cache.ETag = %q, want %q", cache.ETag, `"v3.2.1"`)
+	}
+
+	font := data.GetFont("Hack.tar.xz")
+	url := source.RewriteAssetUrl(font.BrowserDownloadUrl)
+	if want := server.URL + "/assets/v3.2.1/Hack.tar.xz"; url != want {
+		t.Fatalf("RewriteAssetUrl(%q) = %q, want %q", font.BrowserDownloadUrl, url, want)
+	}
+
+	archive := filepath.Join(t.TempDir(), "Hack.tar.xz")
+	err = downloadFont(url, archive)
+	if err != nil {
+		t.Fatalf("downloadFont returned %v", err)
+	}
+	if _, ok := requests["/assets/v3.2.1/Hack.tar.xz"]; !ok {
+		t.Errorf("the archive was not downloaded from the mirror, requests: %v", requests)
+	}
+	content, err := os.ReadFile(archive)
+	if err != nil || string(content) != "archive" {
+		t.Errorf("downloaded %q (%v), want %q", content, err, "archive")
+	}
+}
+
+func TestMirrorSourceNotModified(t *testing.T) {
+	requests := make(map[string]http.Header)
+	server := newMirror(t, requests)
+	source := types.NewSource(server.URL+"/api", "", "")
+
+	cache := types.HttpCache{ETag: `"v3.2.1"`}
+	_, err := GetData(source, &cache)
+	if !errors.Is(err, ErrNotModified) {
+		t.Fatalf("GetData returned %v, want ErrNotModified", err)
+	}
+	if got := requests["/api/releases/latest"].Get("If-None-Match"); got != `"v3.2.1"` {
+		t.Errorf("If-None-Match = %q, want %q", got, `"v3.2.1"`)
+	}
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/getnf/winferior/internal/db"
+	"github.com/getnf/winferior/internal/types"
+)
+
+// only the most recent releases are fetched when looking for the notes of a version
+const maxReleasePages = 5
+
+// ReleaseNotes returns the releases newer than from up to and including to, oldest first.
+// With an empty from only the notes of to are returned. Notes are cached in the db, the
+// GitHub API is only asked when the cache does not cover the range, and when that fails
+// the cached notes are returned along with the error.
+func ReleaseNotes(database *sql.DB, source *types.Source, from string, to string) ([]types.NerdFonts, error) {
+	db.CreateReleasesTable(database)
+
+	cached := db.GetReleases(database)
+	if coversRange(cached, from, to) {
+		return releasesBetween(cached, from, to), nil
+	}
+
+	var fetchErr error
+	for page := 1; page <= maxReleasePages; page++ {
+		var releases []types.NerdFonts
+		fetchErr = getJson(source, source.GetReleasesPageUrl(page), nil, &releases)
+		if fetchErr != nil || len(releases) == 0 {
+			break
+		}
+		db.InsertIntoReleases(database, releases)
+		if coversRange(releases, from, to) || hasReleaseOlderThan(releases, from) {
+			break
+		}
+	}
+
+	return releasesBetween(db.GetReleases(database), from, to), fetchErr
+}
+
+func coversRange(releases []types.NerdFonts, from string, to string) bool {
+	hasTo := false
+	hasFrom := from == ""
+	for _, release := range releases {
+		hasTo = hasTo || release.Version == to
+		hasFrom = hasFrom || types.CompareVersions(release.Version, from) <= 0
+	}
+	return hasTo && hasFrom
+}
+
+func hasReleaseOlderThan(releases []types.NerdFonts, version string) bool {
+	for _, release := range releases {
+		if version == "" || types.CompareVersions(release.Version, version) <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func releasesBetween(releases []types.NerdFonts, from string, to string) []types.NerdFonts {
+	var between []types.NerdFonts
+	for _, release := range releases {
+		if release.Draft {
+			continue
+		}
+		isAfterFrom := from != "" && types.CompareVersions(release.Version, from) > 0
+		if release.Version == to || (isAfterFrom && types.CompareVersions(release.Version, to) <= 0) {
+			between = append(between, release)
+		}
+	}
+	sort.Slice(between, func(i, j int) bool { return types.CompareVersions(between[i].Version, between[j].Version) < 0 })
+	return between
+}
+
+// RenderReleaseNotes renders the notes of releases as markdown for the terminal
+func RenderReleaseNotes(releases []types.NerdFonts) (string, error) {
+	var notes strings.Builder
+	for _, release := range releases {
+		title := release.Version
+		if release.Name != "" && release.Name != release.Version {
+			title += " - " + release.Name
+		}
+		fmt.Fprintf(&notes, "# %v\n\n", title)
+		if date, _, found := strings.Cut(release.PublishedAt, "T"); found {
+			fmt.Fprintf(&notes, "*Released %v*\n\n", date)
+		}
+		if strings.TrimSpace(release.Body) == "" {
+			notes.WriteString("No release notes.\n\n")
+		} else {
+			fmt.Fprintf(&notes, "%v\n\n", release.Body)
+		}
+	}
+
+	renderer, err := glamour.NewTermRenderer(glamour.WithAutoStyle(), glamour.WithWordWrap(100))
+	if err != nil {
+		return "", err
+	}
+	return renderer.Render(notes.String())
+}
+
+func printReleaseNotes(database *sql.DB, source *types.Source, from string, to string) error {
+	releases, err := ReleaseNotes(database, source, from, to)
+	if err != nil {
+		if len(releases) == 0 {
+			return fmt.Errorf("could not fetch the release notes: %v", err)
+		}
+		fmt.Println("could not fetch all release notes, showing the cached ones:", err)
+	}
+	if len(releases) == 0 {
+		fmt.Println("No release notes found")
+		return nil
+	}
+
+	rendered, err := RenderReleaseNotes(releases)
+	if err != nil {
+		return err
+	}
+	fmt.Print(rendered)
+
+	return nil
+}
+
+// oldestVersion returns the oldest installed version of fonts
+func oldestVersion(fonts []types.Font) string {
+	var versions []string
+	for _, font := range fonts {
+		versions = append(versions, font.InstalledVersion)
+	}
+	if len(versions) == 0 {
+		return ""
+	}
+	types.SortVersions(versions)
+	return versions[0]
+}
+
+// HandleChangelog shows the notes between the installed and the available version of
+// font, or of the oldest installed font when no font is given
+func HandleChangelog(cmd *types.ChangelogCmd, database *sql.DB, data types.NerdFonts, source *types.Source, correct CorrectFunc) error {
+	to := data.GetVersion()
+	var from string
+
+	if cmd.Font != "" {
+		// installed fonts may have been dropped from the catalog
+		known := data.GetFontsNames()
+		for _, name := range installedFontNames(database) {
+			if !slices.Contains(known, name) {
+				known = append(known, name)
+			}
+		}
+		name, err := resolveFontName(cmd.Font, known, FontAliases(database), "is not a Nerd Font", correct)
+		if err != nil {
+			return err
+		}
+		if !db.IsFontInstalled(database, name) {
+			return printReleaseNotes(database, source, "", to)
+		}
+		installedFont := db.GetInstalledFont(database, types.Font{Name: name})
+		to = db.GetVersion(database, installedFont.Channel)
+		from = installedFont.InstalledVersion
+		if !IsUpdateAvilable(to, from) {
+			fmt.Printf("%v is up to date (%v)\n", name, from)
+			return nil
+		}
+	} else {
+		from = oldestVersion(db.GetInstalledFonts(database))
+		if from != "" && !IsUpdateAvilable(to, from) {
+			fmt.Printf("All installed fonts are up to date (%v)\n", to)
+			return nil
+		}
+	}
+
+	return printReleaseNotes(database, source, from, to)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/getnf/winferior/internal/preview"
+	"github.com/getnf/winferior/internal/types"
+)
+
+// HandleCompare renders the same sample in each of the fonts into one png or html sheet.
+// Fonts are read from the installed files or cached archives, missing ones are only
+// downloaded when asked to.
+func HandleCompare(cmd *types.CompareCmd, database *sql.DB, data types.NerdFonts, source *types.Source, opts types.Options, correct CorrectFunc) error {
+	if !slices.Contains(types.Variants, cmd.Variant) {
+		return fmt.Errorf("unknown variant %q, expected one of %v", cmd.Variant, strings.Join(types.Variants, ", "))
+	}
+	format := strings.ToLower(filepath.Ext(cmd.Out))
+	if format != ".png" && format != ".html" && format != ".htm" {
+		return fmt.Errorf("unknown output format %q, use a .png or .html file", format)
+	}
+
+	names, err := ResolveFontNames(cmd.Fonts, data.GetFontsNames(), FontAliases(database), "is not a Nerd Font", correct)
+	if err != nil {
+		return err
+	}
+	var fonts []preview.SheetFont
+	for _, name := range names {
+		var fileName string
+		var fontData []byte
+		var err error
+		if cmd.Download {
+			fileName, fontData, err = LoadPreviewFont(data, source, name, cmd.Variant, opts)
+		} else {
+			fileName, fontData, err = CachedPreviewFont(data, name, cmd.Variant, opts)
+		}
+		if errors.Is(err, ErrNotCached) {
+			return fmt.Errorf("%v: %v, install it, keep its archive with -k or pass --download", name, err)
+		}
+		if err != nil {
+			return fmt.Errorf("%v: %v", name, err)
+		}
+		fonts = append(fonts, preview.SheetFont{Name: name, FileName: fileName, Data: fontData})
+	}
+
+	lines := preview.CompareLines()
+	if cmd.Text != "" {
+		lines = preview.ParseLines(cmd.Text)
+	}
+	sample := preview.NewSample(lines, cmd.Size)
+
+	write := preview.WriteSheetPng
+	if format != ".png" {
+		write = preview.WriteSheetHtml
+	}
+	err = write(cmd.Out, fonts, sample)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote a comparison of %v to %v\n", strings.Join(names, ", "), cmd.Out)
+	return nil
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/getnf/winferior/internal/config"
+	"github.com/getnf/winferior/internal/types"
+)
+
+func HandleConfig(cmd *types.ConfigCmd, cfg config.Config) error {
+	switch {
+	case cmd.Get != nil:
+		value, err := cfg.Get(cmd.Get.Key)
+		if err != nil {
+			return err
+		}
+		fmt.Println(value)
+	case cmd.Set != nil:
+		// only the file is rewritten, values coming from the environment stay out of it
+		path := config.GetConfigPath()
+		fileConfig, err := config.LoadFile(path)
+		if err != nil {
+			return err
+		}
+		err = fileConfig.Set(cmd.Set.Key, cmd.Set.Value)
+		if err != nil {
+			return err
+		}
+		err = fileConfig.Validate()
+		if err != nil {
+			return err
+		}
+		err = fileConfig.Save(path)
+		if err != nil {
+			return fmt.Errorf("error writing config file %v: %v", path, err)
+		}
+		if config.IsFromEnv(cmd.Set.Key) {
+			fmt.Printf("%v is overridden by %v\n", cmd.Set.Key, config.EnvName(cmd.Set.Key))
+		}
+	default:
+		writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintf(writer, "# %v\n", config.GetConfigPath())
+		for _, key := range config.Keys() {
+			value, _ := cfg.Get(key)
+			if key == "github_token" && value != "" {
+				value = "<hidden>"
+			}
+			if config.IsFromEnv(key) {
+				value += "\t(from " + config.EnvName(key) + ")"
+			}
+			fmt.Fprintf(writer, "%v\t= %v\n", key, value)
+		}
+		writer.Flush()
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/getnf/winferior/internal/config"
+	"github.com/getnf/winferior/internal/db"
+	"github.com/getnf/winferior/internal/types"
+	"github.com/lithammer/fuzzysearch/fuzzy"
+)
+
+// Glyphs returns the glyphs of release version. They are fetched once per release and
+// cached in the db, when fetching fails the glyphs of an older release are returned along
+// with the error. Without a known release only the cached glyphs are returned.
+func Glyphs(database *sql.DB, source *types.Source, version string) ([]types.Glyph, error) {
+	db.CreateGlyphsTable(database)
+
+	if version == "" {
+		glyphs := db.GetGlyphs(database)
+		if len(glyphs) == 0 {
+			return nil, fmt.Errorf("could not fetch the glyph names: the catalog has no release yet")
+		}
+		return glyphs, nil
+	}
+	if db.GetGlyphsVersion(database) == version {
+		return db.GetGlyphs(database), nil
+	}
+
+	glyphs, err := fetchGlyphNames(source.GetGlyphNamesUrl(version))
+	if err != nil {
+		return db.GetGlyphs(database), fmt.Errorf("could not fetch the glyph names: %v", err)
+	}
+	db.ReplaceGlyphs(database, glyphs, version)
+
+	return glyphs, nil
+}
+
+func fetchGlyphNames(url string) ([]types.Glyph, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%v returned %v", url, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.ParseGlyphNames(body)
+}
+
+// SearchGlyphs fuzzy matches query against the glyph names, best matches first. The nf-
+// prefix of the cheat sheet class names is ignored and a codepoint matches its glyph.
+func SearchGlyphs(glyphs []types.Glyph, query string, limit int) []types.Glyph {
+	query = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(query)), "nf-")
+
+	var matches []types.Glyph
+	byName := make(map[string]types.Glyph)
+	var names []string
+	for _, glyph := range glyphs {
+		if strings.EqualFold(glyph.Code, strings.TrimPrefix(query, "u+")) {
+			matches = append(matches, glyph)
+			continue
+		}
+		byName[glyph.Name] = glyph
+		names = append(names, glyph.Name)
+	}
+
+	ranks := fuzzy.RankFindFold(query, names)
+	sort.Stable(ranks)
+	for _, rank := range ranks {
+		matches = append(matches, byName[rank.Target])
+	}
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+func printGlyphs(glyphs []types.Glyph, output string) {
+	if output == config.OutputJson {
+		type listedGlyph struct {
+			Name      string            `json:"name"`
+			Char      string            `json:"char"`
+			Codepoint string            `json:"codepoint"`
+			Escapes   map[string]string `json:"escapes"`
+		}
+		listedGlyphs := []listedGlyph{}
+		for _, glyph := range glyphs {
+			listed := listedGlyph{Name: glyph.Name, Char: glyph.Char, Codepoint: fmt.Sprintf("U+%04X", glyph.Codepoint()), Escapes: make(map[string]string)}
+			for _, language := range types.GlyphEscapes {
+				listed.Escapes[language] = glyph.Escape(language)
+			}
+			listedGlyphs = append(listedGlyphs, listed)
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(listedGlyphs)
+		return
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(writer, "Glyph:\tName:\tCodepoint:\tGo:\tShell:\tPowerShell:\tLua:")
+	for _, glyph := range glyphs {
+		fmt.Fprintf(writer, "%v\t%v\tU+%04X\t%v\t%v\t%v\t%v\n", glyph.Char, glyph.Name, glyph.Codepoint(),
+			glyph.Escape(types.EscapeGo), glyph.Escape(types.EscapeShell), glyph.Escape(types.EscapePowerShell), glyph.Escape(types.EscapeLua))
+	}
+	writer.Flush()
+}
+
+// LoadGlyphs returns the glyphs of the catalog release, falling back to the cached ones
+func LoadGlyphs(database *sql.DB, data types.NerdFonts, source *types.Source) ([]types.Glyph, error) {
+	glyphs, err := Glyphs(database, source, data.GetVersion())
+	if err != nil {
+		if len(glyphs) == 0 {
+			return nil, err
+		}
+		fmt.Println(err, "using the cached glyph names")
+	}
+	return glyphs, nil
+}
+
+func HandleGlyphSearch(cmd *types.GlyphSearchCmd, glyphs []types.Glyph, output string) error {
+	matches := SearchGlyphs(glyphs, cmd.Query, cmd.Limit)
+	if len(matches) == 0 {
+		return fmt.Errorf("no glyph matches %q", cmd.Query)
+	}
+	printGlyphs(matches, output)
+	return nil
+}
+
+// GlyphFontName is the font large glyph previews are drawn with, it holds every glyph
+const GlyphFontName = "NerdFontsSymbolsOnly"
+
+// GlyphPreviewFont returns a font file holding the glyphs of Nerd Fonts. Every patched
+// font holds them, the symbols font is tried first and then the installed fonts. When
+// none of them is installed or cached the symbols font is downloaded if download is set,
+// otherwise ErrNotCached is returned.
+func GlyphPreviewFont(database *sql.DB, data types.NerdFonts, source *types.Source, opts types.Options, download bool) ([]byte, error) {
+	names := []string{GlyphFontName}
+	for _, font := range db.GetInstalledFonts(database) {
+		names = append(names, font.Name)
+	}
+	for _, name := range names {
+		if _, fontData, err := CachedPreviewFont(data, name, types.VariantDefault, opts); err == nil {
+			return fontData, nil
+		}
+	}
+
+	if !download {
+		return nil, ErrNotCached
+	}
+	_, fontData, err := LoadPreviewFont(data, source, GlyphFontName, types.VariantDefault, opts)
+	return fontData, err
+}
@@ -2,22 +2,26 @@ package handlers
 
 import (
 	"archive/tar"
+	"bytes"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"text/tabwriter"
 	"time"
 
 	"github.com/briandowns/spinner"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/getnf/winferior/internal/config"
 	"github.com/getnf/winferior/internal/db"
+	"github.com/getnf/winferior/internal/theme"
 	"github.com/getnf/winferior/internal/types"
 	"github.com/getnf/winferior/internal/utils"
 	"github.com/lithammer/fuzzysearch/fuzzy"
@@ -27,81 +31,46 @@ import (
 	"golang.org/x/sys/windows/registry"
 )
 
-func SetupDB(database *sql.DB, remoteData types.NerdFonts) {
-	db.CreateVersionTable(database)
-	db.CreateFontsTable(database)
-	db.CreateInstalledFontsTable(database)
-
-	if db.TableIsEmpty(database, "version") || IsUpdateAvilable(remoteData.GetVersion(), db.GetVersion(database)) {
-		db.InsertIntoVersion(database, remoteData.GetVersion())
-		fmt.Println("Updated fonts version")
-	}
-
-	if db.TableIsEmpty(database, "fonts") || IsUpdateAvilable(remoteData.GetVersion(), db.GetVersion(database)) {
-		db.DeleteFontsTable(database)
-		db.CreateFontsTable(database)
-		db.InsertIntoFonts(database, remoteData.GetFonts())
-		fmt.Println("Updating local fonts db")
-	}
-}
-
-func GetData() (types.NerdFonts, error) {
-	url := "https://api.github.com/repos/ryanoasis/nerd-fonts/releases/latest"
-	resp, err := http.Get(url)
-	if err != nil {
-		return types.NerdFonts{}, err
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return types.NerdFonts{}, err
-	}
-
-	var data types.NerdFonts
-	err = json.Unmarshal(body, &data)
-	if err != nil {
-		log.Fatalln(err)
-	}
-	return data, nil
-}
-
-func downloadFont(fontURL string, path string, name string) (string, error) {
-	fullPath := path + "/" + name + ".tar.xz"
+func downloadFont(fontURL string, fullPath string) error {
 	resp, err := http.Get(fontURL)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	if resp.StatusCode != 200 {
-		return "", err
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%v returned %v", fontURL, resp.Status)
 	}
 
 	// Make sure the path exists
+	path := filepath.Dir(fullPath)
 	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
-		err := os.Mkdir(path, os.ModePerm)
+		err := os.MkdirAll(path, os.ModePerm)
 		if err != nil {
-			return "", err
+			return err
 		}
 	}
 
 	// Create the file
 	out, err := os.Create(fullPath)
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	defer out.Close()
 	// Write the body to file
 	_, err = io.Copy(out, resp.Body)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	return fullPath, nil
+	return nil
 }
 
-// extractTar extracts files from a tar archive provided in the reader
-func extractFont(archivePath string, extractPath string, name string) ([]string, error) {
+// extractFont extracts the files of the wanted variants from a font archive and returns
+// the names of the extracted font files, non-font files like licenses are extracted but not returned
+func extractFont(archivePath string, extractPath string, name string, opts types.Options) ([]string, error) {
 	var listOfInstalledFonts []string
 
 	// Decompress the xz stream
@@ -130,6 +99,11 @@ func extractFont(archivePath string, extractPath string, name string) ([]string,
 			return []string{""}, err
 		}
 
+		variant := types.FontVariant(header.Name)
+		if variant != "" && !opts.WantsVariant(variant) {
+			continue
+		}
+
 		// Extract the file name from the header
 		fullPath := filepath.Join(extractPath, name, header.Name)
 		extractPath := filepath.Join(extractPath, name)
@@ -151,74 +125,42 @@ func extractFont(archivePath string, extractPath string, name string) ([]string,
 		}
 
 		// Create file with same permissions as in the tar file
-		file, err := os.OpenFile(fullPath, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
+		file, err := os.OpenFile(fullPath, os.O_CREATE|os.O_RDWR|os.O_TRUNC, os.FileMode(header.Mode))
 		if err != nil {
 			return []string{""}, err
 		}
-		defer file.Close()
 
 		// Write file content to disk
 		_, err = io.Copy(file, tarReader)
+		file.Close()
 		if err != nil {
 			return []string{""}, err
 		}
 
-		listOfInstalledFonts = append(listOfInstalledFonts, header.Name)
+		if variant != "" {
+			listOfInstalledFonts = append(listOfInstalledFonts, header.Name)
+		}
 	}
 
 	return listOfInstalledFonts, nil
 }
 
-func InstallFont(font types.Font, downloadPath string, extractPath string, keepTar bool) error {
-	downloadedTar, err := downloadFont(font.BrowserDownloadUrl, downloadPath, font.Name)
-	if err != nil {
-		return fmt.Errorf("error downloading the tar file: %v", err)
-	}
-	extractedTar, err := extractFont(downloadedTar, extractPath, font.Name)
+func installArchive(font types.Font, downloadedTar string, opts types.Options) error {
+	extractedTar, err := extractFont(downloadedTar, opts.ExtractPath, font.Name, opts)
 	if err != nil {
 		return fmt.Errorf("error extracting the tar file: %v", err)
 	}
 	for _, fileName := range extractedTar {
-		err = removeFromRegistry(fileName)
+		err = removeFromRegistry(opts.Scope, fileName)
 		if err != nil {
-			log.Fatalln(err)
-		}
-		err = writeToRegistry(extractPath, font.Name, fileName)
-		if err != nil {
-			log.Fatalln(err)
-		}
-	}
-	if !keepTar {
-		deleteTar(downloadedTar)
-	}
-
-	return nil
-}
-
-func UninstallFont(path string, name string) error {
-	fontPath := filepath.Join(path, name)
-	fontFiles, err := os.ReadDir(fontPath)
-	if err != nil {
-		log.Fatalln(err)
-	}
-
-	var fileNames []string
-
-	if _, err := os.Stat(fontPath); os.IsNotExist(err) {
-		return fmt.Errorf("font %v is not installed", name)
-	} else {
-		for _, file := range fontFiles {
-			fileNames = append(fileNames, file.Name())
+			return err
 		}
-
-		err = os.RemoveAll(fontPath)
+		err = writeToRegistry(opts.Scope, opts.ExtractPath, font.Name, fileName)
 		if err != nil {
 			return err
 		}
-		for _, file := range fileNames {
-			removeFromRegistry(file)
-		}
 	}
+
 	return nil
 }
 
@@ -234,80 +176,234 @@ func deleteTar(tarPath string) error {
 	return nil
 }
 
+// IsUpdateAvilable reports whether remote is a newer version than local, an unknown
+// local version is always older than a valid remote one
 func IsUpdateAvilable(remote string, local string) bool {
-	remoteVersion, err := utils.StringToInt(remote)
-	if err != nil {
-		log.Fatalln(err)
+	return types.CompareVersions(remote, local) > 0
+}
+
+func IsFontUpdatAvilable(database *sql.DB) bool {
+	return len(OutdatedFonts(database)) > 0
+}
+
+// OutdatedFonts returns the installed fonts older than the catalog of their channel with
+// both versions set
+func OutdatedFonts(database *sql.DB) []types.Font {
+	var outdatedFonts []types.Font
+	catalogs := make(map[types.Channel]types.NerdFonts)
+	for _, font := range db.GetInstalledFonts(database) {
+		catalog, ok := catalogs[font.Channel]
+		if !ok {
+			catalog = db.GetCatalog(database, font.Channel)
+			catalogs[font.Channel] = catalog
+		}
+		if catalog.HasFont(font.Name) && IsUpdateAvilable(catalog.GetVersion(), font.InstalledVersion) {
+			font.AddAvailableVersion(catalog.GetVersion())
+			outdatedFonts = append(outdatedFonts, font)
+		}
+	}
+
+	return outdatedFonts
+}
+
+// HandleCheck reports the installed fonts which have updates and whether there are any
+func HandleCheck(database *sql.DB) bool {
+	outdatedFonts := OutdatedFonts(database)
+	if len(outdatedFonts) == 0 {
+		fmt.Println("All installed fonts are up to date")
+		return false
 	}
 
-	localVersion, err := utils.StringToInt(local)
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 4, '\t', tabwriter.AlignRight)
+	fmt.Fprintln(writer, "Name:\tInstalled Version:\tAvailable Version:")
+	for _, font := range outdatedFonts {
+		fmt.Fprintln(writer, font.Name, "\t", font.InstalledVersion, "\t", font.AvailableVersion)
+	}
+	writer.Flush()
+
+	return true
+}
+
+// PlanUpdate plans installing the latest release of the channel each of the installed
+// fonts follows, archives of updates are never kept
+func PlanUpdate(database *sql.DB, fonts []types.Font, source *types.Source, opts types.Options) types.Plan {
+	var plan types.Plan
+	opts.KeepTars = false
+	for _, channel := range []types.Channel{types.ChannelStable, types.ChannelPrerelease} {
+		catalog := db.GetCatalog(database, channel)
+		var fontsToUpdate []types.Font
+		for _, font := range fonts {
+			if font.Channel == channel && catalog.HasFont(font.Name) {
+				fontsToUpdate = append(fontsToUpdate, catalog.GetFont(font.Name))
+			}
+		}
+		plan.Append(PlanInstall(database, fontsToUpdate, catalog.GetVersion(), channel, source, opts))
+	}
+	return plan
+}
+
+// OutdatedFontsNamed returns the outdated fonts among names, or all outdated fonts when
+// names is empty
+func OutdatedFontsNamed(database *sql.DB, names []string, correct CorrectFunc) ([]types.Font, error) {
+	outdatedFonts := OutdatedFonts(database)
+	if len(names) == 0 {
+		return outdatedFonts, nil
+	}
+
+	names, err := ResolveFontNames(names, installedFontNames(database), FontAliases(database), "is not installed", correct)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, err
 	}
-	if remoteVersion > localVersion {
-		return true
-	} else {
-		return false
+	var fonts []types.Font
+	for _, name := range names {
+		isOutdated := func(f types.Font) bool { return f.Name == name }
+		if outdated := utils.Filter(outdatedFonts, isOutdated); len(outdated) > 0 {
+			fonts = append(fonts, outdated[0])
+		} else {
+			fmt.Printf("%v is up to date\n", name)
+		}
 	}
+	return fonts, nil
 }
 
-func IsFontUpdatAvilable(database *sql.DB, data types.NerdFonts) bool {
-	updateCount := 0
-	installedFonts := db.GetInstalledFonts(database)
-	for _, font := range installedFonts {
-		if IsUpdateAvilable(data.GetVersion(), font.InstalledVersion) {
-			updateCount++
+// HandleUpdate moves every outdated font to the latest release of its own channel,
+// outdatedFonts are as returned by OutdatedFonts
+func HandleUpdate(database *sql.DB, source *types.Source, opts types.Options, outdatedFonts []types.Font, showNotes bool) error {
+	if len(outdatedFonts) == 0 {
+		fmt.Println("No updates are available")
+		return nil
+	}
+
+	if showNotes {
+		var targets []string
+		for _, font := range outdatedFonts {
+			targets = append(targets, font.AvailableVersion)
+		}
+		types.SortVersions(targets)
+		err := printReleaseNotes(database, source, oldestVersion(outdatedFonts), targets[len(targets)-1])
+		if err != nil {
+			fmt.Println(err)
 		}
 	}
 
-	return updateCount > 0
+	plan := PlanUpdate(database, outdatedFonts, source, opts)
+	updatedFonts, err := RunPlan(database, plan, opts)
+	if len(updatedFonts) > 0 {
+		fmt.Printf("Updated font(s): %v\n", strings.Join(updatedFonts, ", "))
+	}
+
+	return err
 }
 
-func HandleUpdate(database *sql.DB, data types.NerdFonts, downloadPath string, extractPath string) error {
-	if IsFontUpdatAvilable(database, data) {
-		installedFonts := db.GetInstalledFonts(database)
-		for _, font := range installedFonts {
-			f := data.GetFont(font.Name)
-			err := InstallFont(f, downloadPath, extractPath, false)
-			if err != nil {
-				return err
+// SearchFonts returns all names of fonts matching query or one of their aliases, the best
+// matches first
+func SearchFonts(query string, fonts []string, aliases types.FontAliases) []string {
+	matches := fuzzy.RankFindFold(query, fonts)
+	if normalized := types.NormalizeFontName(query); normalized != "" {
+		var targets []string
+		for alias, name := range aliases {
+			if slices.Contains(fonts, name) {
+				targets = append(targets, alias)
 			}
-			db.UpdateInstalledFont(database, font.Name, data.GetVersion())
 		}
-	} else {
-		fmt.Println("No updates are available")
+		// ties keep the order of targets, which must not depend on the map order
+		sort.Strings(targets)
+		for _, match := range fuzzy.RankFind(normalized, targets) {
+			match.Target = aliases[match.Target]
+			matches = append(matches, match)
+		}
 	}
+	sort.Stable(matches)
 
-	return nil
+	var names []string
+	for _, match := range matches {
+		if !slices.Contains(names, match.Target) {
+			names = append(names, match.Target)
+		}
+	}
+	return names
 }
 
-func FuzzySearchFonts(font string, fonts []string) ([]string, error) {
-	matches := fuzzy.RankFindFold(font, fonts)
-	var match []string
-	sort.Sort(matches)
+// CorrectFunc asks which of the suggestions the user meant by an unknown font name, an
+// empty name skips the font
+type CorrectFunc func(name string, problem string, suggestions []string) (string, error)
+
+// ResolveFontNames maps the font names given by the user to the names among known, see
+// types.MatchFontName. Unknown names are corrected by correct when it is set and there
+// are suggestions, otherwise they are reported with suggestions and returned as error
+// once all names were looked at. problem describes why a name is unknown.
+func ResolveFontNames(names []string, known []string, aliases types.FontAliases, problem string, correct CorrectFunc) ([]string, error) {
+	var resolved []string
+	var unknown []string
+	for _, name := range names {
+		if match, ok := types.MatchFontName(name, known, aliases); ok {
+			if !slices.Contains(resolved, match) {
+				resolved = append(resolved, match)
+			}
+			continue
+		}
 
-	if len(matches) > 0 {
-		var topMatches fuzzy.Ranks
-		if len(matches) > 3 {
-			topMatches = matches[0:3]
+		suggestions := types.SuggestFontNames(name, known, aliases, 3)
+		if correct != nil && len(suggestions) > 0 {
+			corrected, err := correct(name, problem, suggestions)
+			if err != nil {
+				return nil, err
+			}
+			if corrected != "" {
+				if !slices.Contains(resolved, corrected) {
+					resolved = append(resolved, corrected)
+				}
+				continue
+			}
+		} else if len(suggestions) > 0 {
+			fmt.Printf("%v %v, did you mean %v?\n", name, problem, strings.Join(suggestions, ", "))
 		} else {
-			size := len(matches)
-			topMatches = matches[0:size]
+			fmt.Printf("%v %v\n", name, problem)
 		}
-		for _, font := range topMatches {
-			match = append(match, font.Target)
-		}
-	} else {
-		return []string{""}, fmt.Errorf("no match found")
+		unknown = append(unknown, name)
+	}
+
+	if len(unknown) > 0 {
+		return resolved, fmt.Errorf("skipped unknown font(s): %v", strings.Join(unknown, ", "))
+	}
+	return resolved, nil
+}
+
+// resolveFontName resolves a single font name, see ResolveFontNames
+func resolveFontName(name string, known []string, aliases types.FontAliases, problem string, correct CorrectFunc) (string, error) {
+	names, err := ResolveFontNames([]string{name}, known, aliases, problem, correct)
+	if err != nil {
+		return "", err
 	}
-	return match, nil
+	return names[0], nil
 }
 
-func writeToRegistry(path string, fontName string, fileName string) error {
+// installedFontNames returns the names of the installed fonts
+func installedFontNames(database *sql.DB) []string {
+	var names []string
+	for _, font := range db.GetInstalledFonts(database) {
+		names = append(names, font.Name)
+	}
+	return names
+}
+
+// registryRoot returns the hive holding the fonts of scope, per-user fonts live in HKCU
+func registryRoot(scope types.Scope) registry.Key {
+	if scope == types.ScopeUser {
+		return registry.CURRENT_USER
+	}
+	return registry.LOCAL_MACHINE
+}
+
+func registryValueName(fileName string) string {
+	return fmt.Sprintf("%s (TrueType)", fileName)
+}
+
+func writeToRegistry(scope types.Scope, path string, fontName string, fileName string) error {
 	fullPath := filepath.Join(path, fontName, fileName)
 	k, err := registry.OpenKey(
-		registry.LOCAL_MACHINE,
-		`SOFTWARE\Microsoft\Windows NT\CurrentVersion\Fonts`,
+		registryRoot(scope),
+		fontsRegistryKey,
 		registry.WRITE)
 	if err != nil {
 		os.Remove(fullPath)
@@ -315,8 +411,7 @@ func writeToRegistry(path string, fontName string, fileName string) error {
 	}
 	defer k.Close()
 
-	valueName := fmt.Sprintf("%s (TrueType)", fileName)
-	err = k.SetStringValue(valueName, fullPath)
+	err = k.SetStringValue(registryValueName(fileName), fullPath)
 	if err != nil {
 		os.Remove(fullPath)
 		return fmt.Errorf("error writing to registry: %w", err)
@@ -325,17 +420,17 @@ func writeToRegistry(path string, fontName string, fileName string) error {
 	return nil
 }
 
-func removeFromRegistry(name string) error {
+func removeFromRegistry(scope types.Scope, name string) error {
 	k, err := registry.OpenKey(
-		registry.LOCAL_MACHINE,
-		`SOFTWARE\Microsoft\Windows NT\CurrentVersion\Fonts`,
+		registryRoot(scope),
+		fontsRegistryKey,
 		registry.WRITE)
 	if err != nil {
 		return fmt.Errorf("error opening registry key: %w", err)
 	}
 	defer k.Close()
 
-	valueName := fmt.Sprintf("%s (TrueType)", name)
+	valueName := registryValueName(name)
 
 	// Check if the value exists before attempting to remove it
 	exists, err := valueExistsInRegistry(k, valueName)
@@ -384,6 +479,7 @@ func FontsWithVersion(database *sql.DB, fonts []types.Font, version string) []ty
 		if db.IsFontInstalled(database, font.Name) {
 			installedFont := db.GetInstalledFont(database, font)
 			font.AddInstalledVersion(installedFont.InstalledVersion)
+			font.InstalledAt = installedFont.InstalledAt
 		} else {
 			font.AddInstalledVersion("-")
 		}
@@ -393,90 +489,169 @@ func FontsWithVersion(database *sql.DB, fonts []types.Font, version string) []ty
 	return results
 }
 
-func ListFonts(fonts []types.Font, onlyInstalled bool) {
-	isInstalledFont := func(x types.Font) bool { return x.InstalledVersion != "-" }
-	if onlyInstalled {
-		fonts = utils.Filter(fonts, isInstalledFont)
+// ListFonts prints fonts filtered and sorted as asked for by cmd
+func ListFonts(fonts []types.Font, cmd *types.ListCmd, aliases types.FontAliases, output string) error {
+	fonts, err := FilterFonts(fonts, cmd, aliases)
+	if err != nil {
+		return err
 	}
+	showSize := cmd.Size
+	showDate := cmd.Sort == types.SortInstalledDate
 
-	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 4, '\t', tabwriter.AlignRight)
+	if output == config.OutputJson {
+		listFontsJson(fonts, showSize)
+		return nil
+	}
+	if len(fonts) == 0 {
+		if cmd.Installed && cmd.Query == "" && !cmd.Outdated && !cmd.Ligatures && !cmd.Monospace {
+			fmt.Println("No fonts have been installed yet")
+		} else {
+			fmt.Println("No fonts match")
+		}
+		return nil
+	}
 
-	fmt.Fprintln(writer, "Name:\tAvailable Version:\tInstalled Version:")
+	// the table is colored line by line once it is aligned, tabwriter would count the
+	// escape sequences of the colors as text
+	var buffer bytes.Buffer
+	writer := tabwriter.NewWriter(&buffer, 0, 8, 4, '\t', tabwriter.AlignRight)
+	current := theme.Current()
+	styles := []lipgloss.Style{lipgloss.NewStyle()}
 
-	if len(fonts) == 0 && onlyInstalled {
-		fmt.Println("No fonts have been installed yet")
-		return
+	header := "Name:\tAvailable Version:\tInstalled Version:"
+	if showSize {
+		header += "\tArchive Size:\tInstalled Size:"
+	}
+	if showDate {
+		header += "\tInstalled On:"
 	}
+	fmt.Fprintln(writer, header)
+
 	for _, font := range fonts {
-		fmt.Fprintln(writer, font.Name, "\t", font.AvailableVersion, "\t", font.InstalledVersion)
+		installedVersion := font.InstalledVersion
+		style := lipgloss.NewStyle()
+		if font.InstalledVersion != "-" && IsUpdateAvilable(font.AvailableVersion, font.InstalledVersion) {
+			installedVersion += " (outdated)"
+			style = current.AccentStyle()
+		} else if font.InstalledVersion != "-" {
+			style = current.SelectedStyle()
+		}
+		if !current.HasColors() {
+			style = lipgloss.NewStyle()
+		}
+		styles = append(styles, style)
+		line := fmt.Sprint(font.Name, "\t", font.AvailableVersion, "\t", installedVersion)
+		if showSize {
+			line += fmt.Sprint("\t", formatSize(font.Size), "\t", formatSize(font.InstalledSize))
+		}
+		if showDate {
+			line += fmt.Sprint("\t", installedDate(font))
+		}
+		fmt.Fprintln(writer, line)
 	}
 	writer.Flush()
+
+	for i, line := range strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n") {
+		fmt.Println(styles[i].Render(line))
+	}
+	return nil
 }
 
-func HandleInstall(args types.Args, database *sql.DB, data types.NerdFonts, downloadPath string, extractPath string) error {
-	var installedFonts []string
-	var fontsToInstall []string
-	for _, font := range args.Install.Fonts {
-		if db.FontExists(database, font) {
-			fontsToInstall = append(fontsToInstall, font)
-		} else {
-			fmt.Printf("%v is not a nerd font\n", font)
-			fuzzySearchedFont, err := FuzzySearchFonts(font, data.GetFontsNames())
-			if err != nil {
-				return fmt.Errorf("did you mean: %v: ", err)
-			}
-			// fmt.Printf("did you mean: %v\n", fuzzySearchedFont)
-			return fmt.Errorf("did you mean: %v: ", fuzzySearchedFont)
+func listFontsJson(fonts []types.Font, showSize bool) {
+	type listedFont struct {
+		Name             string `json:"name"`
+		AvailableVersion string `json:"available_version"`
+		InstalledVersion string `json:"installed_version,omitempty"`
+		Outdated         bool   `json:"outdated"`
+		InstalledAt      string `json:"installed_at,omitempty"`
+		Family           string `json:"family,omitempty"`
+		License          string `json:"license,omitempty"`
+		Ligatures        bool   `json:"ligatures"`
+		Monospace        bool   `json:"monospace"`
+		ArchiveSize      int64  `json:"archive_size,omitempty"`
+		InstalledSize    int64  `json:"installed_size,omitempty"`
+	}
+
+	listedFonts := []listedFont{}
+	for _, font := range fonts {
+		listed := listedFont{
+			Name:             font.Name,
+			AvailableVersion: font.AvailableVersion,
+			Family:           font.Meta.Family,
+			License:          font.Meta.GetLicense(),
+			Ligatures:        font.Meta.Ligatures,
+			Monospace:        font.Meta.Monospace,
 		}
+		if font.InstalledVersion != "-" {
+			listed.InstalledVersion = font.InstalledVersion
+			listed.InstalledAt = font.InstalledAt
+			listed.Outdated = IsUpdateAvilable(font.AvailableVersion, font.InstalledVersion)
+		}
+		if showSize {
+			listed.ArchiveSize = font.Size
+			listed.InstalledSize = font.InstalledSize
+		}
+		listedFonts = append(listedFonts, listed)
 	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(listedFonts)
+}
+
+func HandleInstall(args types.Args, database *sql.DB, data types.NerdFonts, source *types.Source, opts types.Options, correct CorrectFunc) error {
+	var installedFonts []string
+	fontsToInstall, unknownErr := ResolveFontNames(args.Install.Fonts, data.GetFontsNames(), FontAliases(database), "is not a Nerd Font", correct)
+	var err error
 	if len(fontsToInstall) > 0 {
+		var fonts []types.Font
 		for _, font := range fontsToInstall {
-			f := data.GetFont(font)
-			err := InstallFont(f, downloadPath, extractPath, args.KeepTars)
-			if err != nil {
-				return err
-			}
-			db.InsertIntoInstalledFonts(database, f, data.GetVersion())
-			installedFonts = append(installedFonts, font)
+			fonts = append(fonts, data.GetFont(font))
 		}
+		plan := PlanInstall(database, fonts, data.GetVersion(), opts.Channel, source, opts)
+		installedFonts, err = RunPlan(database, plan, opts)
 	}
 	if len(installedFonts) > 0 {
 		fmt.Printf("Installed font(s): %v\n", strings.Join(installedFonts, ", "))
 	}
 
-	return nil
+	return errors.Join(err, unknownErr)
 }
 
-func HandleUninstall(args types.Args, database *sql.DB, data types.NerdFonts, extractPath string) error {
+func HandleUninstall(args types.Args, database *sql.DB, data types.NerdFonts, opts types.Options, correct CorrectFunc) error {
 	var fontsToUninstall []string
-	for _, font := range args.Uninstall.Fonts {
-		if db.IsFontInstalled(database, font) {
-			fontsToUninstall = append(fontsToUninstall, font)
-		} else {
-			fmt.Printf("%v is either not installed or is not a nerd font\n", font)
-			fuzzySearchedFont, err := FuzzySearchFonts(font, data.GetFontsNames())
-			if err != nil {
-				return fmt.Errorf("did you mean: %v: ", err)
-			}
-			return fmt.Errorf("did you mean: %v: ", fuzzySearchedFont)
+	if args.Uninstall.All {
+		for _, font := range db.GetInstalledFonts(database) {
+			fontsToUninstall = append(fontsToUninstall, font.Name)
+		}
+		if len(fontsToUninstall) == 0 {
+			fmt.Println("No fonts have been installed yet")
+		}
+	}
+	names, unknownErr := ResolveFontNames(args.Uninstall.Fonts, installedFontNames(database), FontAliases(database), "is not installed", correct)
+	for _, name := range names {
+		// --all already lists every installed font
+		if !slices.Contains(fontsToUninstall, name) {
+			fontsToUninstall = append(fontsToUninstall, name)
 		}
 	}
 	if len(fontsToUninstall) > 0 {
+		plan := PlanUninstall(database, fontsToUninstall, opts)
+		if opts.DryRun {
+			PrintPlan(plan, opts)
+			return unknownErr
+		}
 		s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
 		s.Suffix = " Uninstalling fonts"
 		s.Color("red")
 		s.Start()
-		for _, font := range fontsToUninstall {
-			err := UninstallFont(extractPath, font)
-			if err != nil {
-				s.Stop()
-				return err
-			}
-			db.DeleteInstalledFont(database, font)
+		uninstalledFonts, err := ExecutePlan(database, plan, opts)
+		if len(uninstalledFonts) > 0 {
+			s.FinalMSG = "uninstalled font(s): " + strings.Join(uninstalledFonts, ", ") + "\n"
 		}
-		s.FinalMSG = "uninstalled font(s): " + strings.Join(fontsToUninstall, ", ") + "\n"
 		s.Stop()
+		return errors.Join(err, unknownErr)
 	}
 
-	return nil
+	return unknownErr
 }
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"archive/tar"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/dustin/go-humanize"
+	"github.com/getnf/winferior/internal/config"
+	"github.com/getnf/winferior/internal/db"
+	"github.com/getnf/winferior/internal/types"
+	"github.com/ulikunitz/xz"
+)
+
+// licenses are short texts, anything larger is not read
+const maxLicenseSize = 1 << 20
+
+func licenseFromDir(dir string) string {
+	var license string
+	filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || license != "" || !types.IsLicenseFile(path) {
+			return nil
+		}
+		text, err := os.ReadFile(path)
+		if err == nil && len(text) <= maxLicenseSize {
+			license = types.DetectLicense(string(text))
+		}
+		return nil
+	})
+	return license
+}
+
+func licenseFromArchive(archivePath string) (string, error) {
+	fontArchive, err := os.Open(archivePath)
+	if err != nil {
+		return "", err
+	}
+	defer fontArchive.Close()
+	xzReader, err := xz.NewReader(fontArchive)
+	if err != nil {
+		return "", err
+	}
+
+	tarReader := tar.NewReader(xzReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if header.Typeflag != tar.TypeReg || !types.IsLicenseFile(header.Name) {
+			continue
+		}
+		text, err := io.ReadAll(io.LimitReader(tarReader, maxLicenseSize))
+		if err != nil {
+			return "", err
+		}
+		if license := types.DetectLicense(string(text)); license != "" {
+			return license, nil
+		}
+	}
+}
+
+// ArchiveLicense detects the license of font from the license files of its installation,
+// or of its cached archive when it is not installed. An empty license means none of the
+// files holds a known license.
+func ArchiveLicense(font types.Font, opts types.Options) (string, error) {
+	if license := licenseFromDir(filepath.Join(opts.ExtractPath, font.Name)); license != "" {
+		return license, nil
+	}
+	if !IsCached(font, font.AvailableVersion, opts) {
+		return "", nil
+	}
+	return licenseFromArchive(archivePath(opts.DownloadPath, font.Name, font.AvailableVersion))
+}
+
+// recordArchiveLicense stores the license of a font which was just installed
+func recordArchiveLicense(database *sql.DB, font types.Font, opts types.Options) {
+	license, err := ArchiveLicense(font, opts)
+	if err != nil || license == "" {
+		return
+	}
+	db.CreateFontMetaTable(database)
+	db.SeedFontMeta(database, types.BuiltinFontMeta([]types.Font{font}))
+	db.UpdateArchiveLicense(database, font.Name, license)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// HandleInfo prints the metadata, versions and sizes of a font
+func HandleInfo(cmd *types.InfoCmd, database *sql.DB, data types.NerdFonts, opts types.Options, output string, correct CorrectFunc) error {
+	name, err := resolveFontName(cmd.Font, data.GetFontsNames(), FontAliases(database), "is not a Nerd Font", correct)
+	if err != nil {
+		return err
+	}
+	font := data.GetFont(name)
+	font = FontsWithVersion(database, []types.Font{font}, data.GetVersion())[0]
+	font = FontsWithMeta(database, []types.Font{font})[0]
+
+	if font.Meta.ArchiveLicense == "" {
+		license, err := ArchiveLicense(font, opts)
+		if err == nil && license != "" {
+			db.UpdateArchiveLicense(database, font.Name, license)
+			font.Meta.ArchiveLicense = license
+		}
+	}
+
+	installed := font.InstalledVersion != "-"
+	outdated := installed && IsUpdateAvilable(font.AvailableVersion, font.InstalledVersion)
+	var installedSize int64
+	if installed {
+		installedSize = FontUsage(font, opts).Size
+	}
+
+	if output == config.OutputJson {
+		info := struct {
+			Name             string `json:"name"`
+			Family           string `json:"family,omitempty"`
+			Description      string `json:"description,omitempty"`
+			License          string `json:"license,omitempty"`
+			Ligatures        bool   `json:"ligatures"`
+			Monospace        bool   `json:"monospace"`
+			AvailableVersion string `json:"available_version"`
+			InstalledVersion string `json:"installed_version,omitempty"`
+			InstalledAt      string `json:"installed_at,omitempty"`
+			Outdated         bool   `json:"outdated"`
+			ArchiveSize      int64  `json:"archive_size,omitempty"`
+			InstalledSize    int64  `json:"installed_size,omitempty"`
+			Cached           bool   `json:"cached"`
+		}{
+			Name:             font.Name,
+			Family:           font.Meta.Family,
+			Description:      font.Meta.Description,
+			License:          font.Meta.GetLicense(),
+			Ligatures:        font.Meta.Ligatures,
+			Monospace:        font.Meta.Monospace,
+			AvailableVersion: font.AvailableVersion,
+			Outdated:         outdated,
+			ArchiveSize:      font.Size,
+			InstalledSize:    installedSize,
+			Cached:           IsCached(font, font.AvailableVersion, opts),
+		}
+		if installed {
+			info.InstalledVersion = font.InstalledVersion
+			info.InstalledAt = font.InstalledAt
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(info)
+	}
+
+	orUnknown := func(value string) string {
+		if value == "" {
+			return "unknown"
+		}
+		return value
+	}
+	installedVersion := "not installed"
+	if installed {
+		installedVersion = font.InstalledVersion
+		if outdated {
+			installedVersion += " (outdated)"
+		}
+		if date := installedDate(font); date != "-" {
+			installedVersion += ", on " + date
+		}
+	}
+	archiveSize := "unknown"
+	if font.Size > 0 {
+		archiveSize = humanize.Bytes(uint64(font.Size))
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(writer, "Name:\t%v\n", font.Name)
+	fmt.Fprintf(writer, "Family:\t%v\n", orUnknown(font.Meta.Family))
+	fmt.Fprintf(writer, "Description:\t%v\n", orUnknown(font.Meta.Description))
+	fmt.Fprintf(writer, "License:\t%v\n", orUnknown(font.Meta.GetLicense()))
+	fmt.Fprintf(writer, "Ligatures:\t%v\n", yesNo(font.Meta.Ligatures))
+	fmt.Fprintf(writer, "Monospace:\t%v\n", yesNo(font.Meta.Monospace))
+	fmt.Fprintf(writer, "Available:\t%v (%v)\n", font.AvailableVersion, opts.Channel)
+	fmt.Fprintf(writer, "Installed:\t%v\n", installedVersion)
+	fmt.Fprintf(writer, "Archive size:\t%v\n", archiveSize)
+	if installed {
+		fmt.Fprintf(writer, "Installed size:\t%v\n", humanize.Bytes(uint64(installedSize)))
+	}
+	fmt.Fprintf(writer, "Cached:\t%v\n", yesNo(IsCached(font, font.AvailableVersion, opts)))
+	return writer.Flush()
+}
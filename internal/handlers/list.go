@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getnf/winferior/internal/db"
+	"github.com/getnf/winferior/internal/types"
+	"github.com/getnf/winferior/internal/utils"
+)
+
+// FontsWithMeta adds the stored metadata to fonts, fonts the database has no metadata for
+// get the metadata built into winferior. The metadata of Nerd Fonts is stored when the
+// catalog is refreshed, see refreshFontMeta.
+func FontsWithMeta(database *sql.DB, fonts []types.Font) []types.Font {
+	db.CreateFontMetaTable(database)
+
+	metas := db.GetFontMeta(database)
+	var results []types.Font
+	for _, font := range fonts {
+		meta, ok := metas[font.Name]
+		if !ok {
+			meta = types.BuiltinFontMeta([]types.Font{font})[0]
+		}
+		font.Meta = meta
+		results = append(results, font)
+	}
+	return results
+}
+
+// FontAliases returns the aliases of the catalog fonts, the renames known to winferior
+// which SetupDB stores and the names of the fonts.json of Nerd Fonts once it was fetched
+func FontAliases(database *sql.DB) types.FontAliases {
+	return types.NewFontAliases(db.GetFontAliases(database))
+}
+
+// refreshFontMeta stores the metadata of the fonts.json of release version. Like the catalog
+// it is fetched conditionally, the stored metadata of another release is replaced.
+func refreshFontMeta(database *sql.DB, source *types.Source, version string) error {
+	db.CreateFontMetaTable(database)
+	if version == "" {
+		return nil
+	}
+
+	url := source.GetFontsJsonUrl(version)
+	cache := db.GetHttpCache(database, url)
+	if db.GetFontMetaVersion(database) != version {
+		cache.ETag = ""
+		cache.LastModified = ""
+	}
+
+	metas, err := fetchFontsJson(url, &cache)
+	if errors.Is(err, ErrNotModified) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not fetch the font metadata: %v", err)
+	}
+
+	db.ReplaceFontMeta(database, metas, version)
+	db.InsertFontAliases(database, types.FontMetaAliases(metas))
+	db.UpdateHttpCache(database, cache)
+	return nil
+}
+
+// fetchFontsJson fetches the fonts.json at url, see GetData for cache
+func fetchFontsJson(url string, cache *types.HttpCache) ([]types.FontMeta, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if cache.ETag != "" {
+		req.Header.Set("If-None-Match", cache.ETag)
+	}
+	if cache.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cache.LastModified)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, ErrNotModified
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%v returned %v", url, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	metas, err := types.ParseFontsJson(body)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.Url = url
+	cache.ETag = resp.Header.Get("ETag")
+	cache.LastModified = resp.Header.Get("Last-Modified")
+	return metas, nil
+}
+
+// FilterFonts applies the query, filters and sort order of cmd to fonts, without a sort
+// order matches of the query are sorted by relevance and all other fonts by name
+func FilterFonts(fonts []types.Font, cmd *types.ListCmd, aliases types.FontAliases) ([]types.Font, error) {
+	if cmd.Installed && cmd.NotInstalled {
+		return nil, fmt.Errorf("--installed and --not-installed exclude each other")
+	}
+	if cmd.Outdated && cmd.NotInstalled {
+		return nil, fmt.Errorf("--outdated and --not-installed exclude each other")
+	}
+	switch cmd.Sort {
+	case "", types.SortName, types.SortSize, types.SortInstalledDate:
+	default:
+		return nil, fmt.Errorf("can not sort by %q, sort by %v, %v or %v", cmd.Sort, types.SortName, types.SortSize, types.SortInstalledDate)
+	}
+
+	isInstalled := func(f types.Font) bool { return f.InstalledVersion != "-" }
+	filters := []func(types.Font) bool{}
+	if cmd.Installed {
+		filters = append(filters, isInstalled)
+	}
+	if cmd.NotInstalled {
+		filters = append(filters, func(f types.Font) bool { return !isInstalled(f) })
+	}
+	if cmd.Outdated {
+		filters = append(filters, func(f types.Font) bool {
+			return isInstalled(f) && IsUpdateAvilable(f.AvailableVersion, f.InstalledVersion)
+		})
+	}
+	if cmd.Ligatures {
+		filters = append(filters, func(f types.Font) bool { return f.Meta.Ligatures })
+	}
+	if cmd.Monospace {
+		filters = append(filters, func(f types.Font) bool { return f.Meta.Monospace })
+	}
+	for _, filter := range filters {
+		fonts = utils.Filter(fonts, filter)
+	}
+
+	if cmd.Query != "" {
+		byName := make(map[string]types.Font)
+		var names []string
+		for _, font := range fonts {
+			byName[font.Name] = font
+			names = append(names, font.Name)
+		}
+		var matches []types.Font
+		for _, name := range SearchFonts(cmd.Query, names, aliases) {
+			matches = append(matches, byName[name])
+		}
+		fonts = matches
+	}
+
+	switch {
+	case cmd.Sort == types.SortName || (cmd.Sort == "" && cmd.Query == ""):
+		sort.SliceStable(fonts, func(i, j int) bool { return strings.ToLower(fonts[i].Name) < strings.ToLower(fonts[j].Name) })
+	case cmd.Sort == types.SortSize:
+		sort.SliceStable(fonts, func(i, j int) bool { return fonts[i].Size > fonts[j].Size })
+	case cmd.Sort == types.SortInstalledDate:
+		// the most recently installed first, fonts which are not installed last
+		sort.SliceStable(fonts, func(i, j int) bool { return fonts[i].InstalledAt > fonts[j].InstalledAt })
+	}
+
+	return fonts, nil
+}
+
+// installedDate is the day font was installed on, fonts installed by older versions of
+// winferior have no date
+func installedDate(font types.Font) string {
+	if font.InstalledVersion == "-" {
+		return ""
+	}
+	date, _, _ := strings.Cut(font.InstalledAt, " ")
+	if date == "" {
+		return "-"
+	}
+	return date
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/dustin/go-humanize"
+	"github.com/getnf/winferior/internal/db"
+	"github.com/getnf/winferior/internal/types"
+)
+
+const fontsRegistryKey = `SOFTWARE\Microsoft\Windows NT\CurrentVersion\Fonts`
+
+func archivePath(downloadPath string, name string, version string) string {
+	return filepath.Join(downloadPath, types.ArchiveName(name, version))
+}
+
+// PlanInstall plans installing fonts from the release version of channel, fonts which are
+// already installed get reinstalled and their db entry updated instead of duplicated.
+// Cached archives are reused when they match the catalog.
+func PlanInstall(database *sql.DB, fonts []types.Font, version string, channel types.Channel, source *types.Source, opts types.Options) types.Plan {
+	var plan types.Plan
+	for _, font := range fonts {
+		archive := archivePath(opts.DownloadPath, font.Name, version)
+		if verifyArchive(archive, font) == nil {
+			plan.Add(types.Action{Kind: types.ActionUseCache, Font: font, Path: archive, Size: font.Size})
+		} else {
+			plan.Add(types.Action{Kind: types.ActionDownload, Font: font, Path: source.RewriteAssetUrl(font.BrowserDownloadUrl), Target: archive, Size: font.Size})
+		}
+		plan.Add(types.Action{Kind: types.ActionExtract, Font: font, Path: archive, Target: filepath.Join(opts.ExtractPath, font.Name)})
+		if !opts.KeepTars {
+			plan.Add(types.Action{Kind: types.ActionDeleteArchive, Font: font, Path: archive})
+		}
+		if db.IsFontInstalled(database, font.Name) {
+			installedFont := db.GetInstalledFont(database, font)
+			plan.Add(types.Action{Kind: types.ActionDbUpdate, Font: installedFont, Version: version, Channel: channel})
+		} else {
+			plan.Add(types.Action{Kind: types.ActionDbInsert, Font: font, Version: version, Channel: channel})
+		}
+	}
+	return plan
+}
+
+// PlanUninstall plans removing the files of the named fonts, their registry values and db entries
+func PlanUninstall(database *sql.DB, names []string, opts types.Options) types.Plan {
+	var plan types.Plan
+	for _, name := range names {
+		font := types.Font{Name: name}
+		fontPath := filepath.Join(opts.ExtractPath, name)
+		fontFiles, err := os.ReadDir(fontPath)
+		if err == nil {
+			for _, file := range fontFiles {
+				if types.FontVariant(file.Name()) != "" {
+					plan.Add(types.Action{Kind: types.ActionRemoveRegistryValue, Font: font, Path: file.Name()})
+				}
+			}
+			for _, file := range fontFiles {
+				plan.Add(types.Action{Kind: types.ActionRemoveFile, Font: font, Path: filepath.Join(fontPath, file.Name())})
+			}
+			plan.Add(types.Action{Kind: types.ActionRemoveDir, Font: font, Path: fontPath})
+		}
+		if db.IsFontInstalled(database, name) {
+			plan.Add(types.Action{Kind: types.ActionDbDelete, Font: font})
+		}
+	}
+	return plan
+}
+
+// RunPlan prints the plan when running dry, otherwise it executes it
+func RunPlan(database *sql.DB, plan types.Plan, opts types.Options) ([]string, error) {
+	if opts.DryRun {
+		PrintPlan(plan, opts)
+		return nil, nil
+	}
+	return ExecutePlan(database, plan, opts)
+}
+
+// ExecutePlan applies the actions of plan in order, downloads run in parallel up front.
+// When an action fails the remaining actions of that font are skipped, the names of the
+// fonts whose actions all succeeded are returned along with the errors of the others.
+// Actions which do not belong to a font, like deleting the database, never get skipped.
+func ExecutePlan(database *sql.DB, plan types.Plan, opts types.Options) ([]string, error) {
+	failed := make(map[string]bool)
+	var errs []error
+
+	downloadErrs := downloadArchives(plan.GetActions(types.ActionDownload), opts)
+
+	for _, action := range plan.Actions {
+		name := action.Font.Name
+		if failed[name] {
+			continue
+		}
+
+		var err error
+		switch action.Kind {
+		case types.ActionDownload:
+			err = downloadErrs[name]
+		case types.ActionExtract:
+			err = installArchive(action.Font, action.Path, opts)
+		case types.ActionDeleteArchive:
+			err = deleteTar(action.Path)
+		case types.ActionRemoveRegistryValue:
+			err = removeFromRegistry(opts.Scope, action.Path)
+		case types.ActionRemoveFile:
+			err = os.Remove(action.Path)
+		case types.ActionRemoveDir:
+			err = os.RemoveAll(action.Path)
+		case types.ActionDbInsert:
+			db.InsertIntoInstalledFonts(database, action.Font, action.Version, action.Channel)
+			recordArchiveLicense(database, action.Font, opts)
+		case types.ActionDbUpdate:
+			// an upsert, an earlier plan may have uninstalled the font since this plan was made
+			db.InsertIntoInstalledFonts(database, action.Font, action.Version, action.Channel)
+			recordArchiveLicense(database, action.Font, opts)
+		case types.ActionDbDelete:
+			db.DeleteInstalledFont(database, name)
+		case types.ActionDeleteDb:
+			database.Close()
+			err = os.Remove(action.Path)
+		}
+
+		if err != nil && name == "" {
+			errs = append(errs, err)
+		} else if err != nil {
+			failed[name] = true
+			errs = append(errs, fmt.Errorf("%v: %v", name, err))
+		}
+	}
+
+	var done []string
+	for _, name := range plan.GetFontsNames() {
+		if name != "" && !failed[name] {
+			done = append(done, name)
+		}
+	}
+
+	return done, errors.Join(errs...)
+}
+
+func downloadArchives(downloads []types.Action, opts types.Options) map[string]error {
+	errs := make(map[string]error)
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, max(opts.Parallelism, 1))
+	for _, download := range downloads {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			err := downloadFont(download.Path, download.Target)
+			if err == nil {
+				err = verifyArchive(download.Target, download.Font)
+				if err != nil {
+					os.Remove(download.Target)
+				}
+			}
+			if err != nil {
+				mutex.Lock()
+				errs[download.Font.Name] = fmt.Errorf("error downloading the tar file: %v", err)
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// PrintPlan prints the actions of plan grouped by font
+func PrintPlan(plan types.Plan, opts types.Options) {
+	if plan.IsEmpty() {
+		fmt.Println("Nothing to do")
+		return
+	}
+
+	fmt.Println("Dry run, nothing has been changed. The following would be done:")
+	for _, name := range plan.GetFontsNames() {
+		if name == "" {
+			fmt.Printf("\nCache and database\n")
+		} else {
+			fmt.Printf("\n%v\n", name)
+		}
+		for _, action := range plan.Actions {
+			if action.Font.Name == name {
+				fmt.Printf("  %-22v %v\n", action.Kind, describeAction(action, opts))
+			}
+		}
+	}
+
+	if downloads := plan.GetActions(types.ActionDownload); len(downloads) > 0 {
+		fmt.Printf("\n%v download(s), %v in total\n", len(downloads), humanize.Bytes(uint64(plan.GetDownloadSize())))
+	}
+	if cached := plan.GetActions(types.ActionUseCache); len(cached) > 0 {
+		fmt.Printf("%v archive(s) reused from the cache\n", len(cached))
+	}
+}
+
+func describeAction(action types.Action, opts types.Options) string {
+	registryKey := opts.Scope.RegistryHive() + `\` + fontsRegistryKey
+	switch action.Kind {
+	case types.ActionDownload:
+		size := "size unknown"
+		if action.Size > 0 {
+			size = humanize.Bytes(uint64(action.Size))
+		}
+		return fmt.Sprintf("%v (%v) to %v", action.Path, size, action.Target)
+	case types.ActionExtract:
+		variants := "all variants"
+		if len(opts.Variants) > 0 {
+			variants = "variants " + strings.Join(opts.Variants, ", ")
+		}
+		return fmt.Sprintf("%v into %v, adding the font files of %v to %v", action.Path, action.Target, variants, registryKey)
+	case types.ActionRemoveRegistryValue:
+		return fmt.Sprintf(`%v\%v`, registryKey, registryValueName(action.Path))
+	case types.ActionDbInsert:
+		return fmt.Sprintf("installedFonts: %v %v (%v)", action.Font.Name, action.Version, action.Channel)
+	case types.ActionDbUpdate:
+		return fmt.Sprintf("installedFonts: %v %v -> %v (%v)", action.Font.Name, action.Font.InstalledVersion, action.Version, action.Channel)
+	case types.ActionDbDelete:
+		return fmt.Sprintf("installedFonts: %v", action.Font.Name)
+	case types.ActionUseCache, types.ActionDeleteArchive, types.ActionRemoveFile, types.ActionRemoveDir, types.ActionDeleteDb:
+		if action.Size > 0 {
+			return fmt.Sprintf("%v (%v)", action.Path, humanize.Bytes(uint64(action.Size)))
+		}
+		return action.Path
+	default:
+		return action.Path
+	}
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"archive/tar"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/getnf/winferior/internal/preview"
+	"github.com/getnf/winferior/internal/types"
+	"github.com/ulikunitz/xz"
+)
+
+// pickFontFile picks the file previews are rendered with, the regular style of variant
+// if there is one, otherwise the first font file of variant or any font file at all
+func pickFontFile(fileNames []string, variant string) string {
+	var picked string
+	bestScore := -1
+	for _, fileName := range fileNames {
+		fileVariant := types.FontVariant(fileName)
+		if fileVariant == "" {
+			continue
+		}
+		score := 0
+		if fileVariant == variant {
+			score += 2
+		}
+		if strings.Contains(strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName)), "-Regular") {
+			score++
+		}
+		if score > bestScore {
+			picked = fileName
+			bestScore = score
+		}
+	}
+	return picked
+}
+
+func readFontFromDir(dir string, variant string) (string, []byte, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return "", nil, err
+	}
+	var fileNames []string
+	for _, file := range files {
+		fileNames = append(fileNames, file.Name())
+	}
+	picked := pickFontFile(fileNames, variant)
+	if picked == "" {
+		return "", nil, fmt.Errorf("%v contains no font files", dir)
+	}
+	fontData, err := os.ReadFile(filepath.Join(dir, picked))
+	return picked, fontData, err
+}
+
+func readFontFromArchive(archivePath string, variant string) (string, []byte, error) {
+	fontArchive, err := os.Open(archivePath)
+	if err != nil {
+		return "", nil, err
+	}
+	defer fontArchive.Close()
+	xzReader, err := xz.NewReader(fontArchive)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// the archive is read twice as the file to pick is only known after listing it
+	var fileNames []string
+	tarReader := tar.NewReader(xzReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		fileNames = append(fileNames, header.Name)
+	}
+	picked := pickFontFile(fileNames, variant)
+	if picked == "" {
+		return "", nil, fmt.Errorf("%v contains no font files", filepath.Base(archivePath))
+	}
+
+	_, err = fontArchive.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", nil, err
+	}
+	xzReader, err = xz.NewReader(fontArchive)
+	if err != nil {
+		return "", nil, err
+	}
+	tarReader = tar.NewReader(xzReader)
+	for {
+		header, err := tarReader.Next()
+		if err != nil {
+			return "", nil, err
+		}
+		if header.Name == picked {
+			fontData, err := io.ReadAll(tarReader)
+			return filepath.Base(picked), fontData, err
+		}
+	}
+}
+
+var ErrNotCached = errors.New("the font is neither installed nor cached")
+
+// CachedPreviewFont returns the name and contents of a font file of name in variant, read
+// from the installed files or from a cached archive. ErrNotCached is returned if neither exists.
+func CachedPreviewFont(data types.NerdFonts, name string, variant string, opts types.Options) (string, []byte, error) {
+	fontDir := filepath.Join(opts.ExtractPath, name)
+	if fileName, fontData, err := readFontFromDir(fontDir, variant); err == nil && types.FontVariant(fileName) == variant {
+		return fileName, fontData, nil
+	}
+
+	if !data.HasFont(name) {
+		return "", nil, fmt.Errorf("%v is not a nerd font", name)
+	}
+
+	archive := archivePath(opts.DownloadPath, name, data.GetVersion())
+	if verifyArchive(archive, data.GetFont(name)) == nil {
+		return readFontFromArchive(archive, variant)
+	}
+
+	return "", nil, ErrNotCached
+}
+
+// LoadPreviewFont returns a font file like CachedPreviewFont, fonts which are not cached
+// are downloaded to a temporary directory which is removed afterwards
+func LoadPreviewFont(data types.NerdFonts, source *types.Source, name string, variant string, opts types.Options) (string, []byte, error) {
+	fileName, fontData, err := CachedPreviewFont(data, name, variant, opts)
+	if !errors.Is(err, ErrNotCached) {
+		return fileName, fontData, err
+	}
+	font := data.GetFont(name)
+
+	tempDir, err := os.MkdirTemp("", "winferior-preview-")
+	if err != nil {
+		return "", nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	archive := filepath.Join(tempDir, types.ArchiveName(name, data.GetVersion()))
+	err = downloadFont(source.RewriteAssetUrl(font.BrowserDownloadUrl), archive)
+	if err == nil {
+		err = verifyArchive(archive, font)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("error downloading the tar file: %v", err)
+	}
+
+	return readFontFromArchive(archive, variant)
+}
+
+func HandlePreview(cmd *types.PreviewCmd, database *sql.DB, data types.NerdFonts, source *types.Source, opts types.Options, correct CorrectFunc) error {
+	if !slices.Contains(types.Variants, cmd.Variant) {
+		return fmt.Errorf("unknown variant %q, expected one of %v", cmd.Variant, strings.Join(types.Variants, ", "))
+	}
+
+	name, err := resolveFontName(cmd.Font, data.GetFontsNames(), FontAliases(database), "is not a Nerd Font", correct)
+	if err != nil {
+		return err
+	}
+	fileName, fontData, err := LoadPreviewFont(data, source, name, cmd.Variant, opts)
+	if err != nil {
+		return err
+	}
+
+	lines := preview.DefaultLines(cmd.Font)
+	if cmd.Text != "" {
+		lines = preview.ParseLines(cmd.Text)
+	}
+	img, err := preview.Render(fontData, preview.NewSample(lines, cmd.Size))
+	if err != nil {
+		return fmt.Errorf("%v: %v", fileName, err)
+	}
+
+	out := cmd.Out
+	if out == "" {
+		out = cmd.Font + ".png"
+	}
+	err = preview.WritePng(out, img)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote a preview of %v to %v\n", fileName, out)
+	return nil
+}
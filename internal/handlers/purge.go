@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/dustin/go-humanize"
+	"github.com/getnf/winferior/internal/db"
+	"github.com/getnf/winferior/internal/types"
+)
+
+// PlanPurge plans uninstalling every installed font and deleting the cached archives, see
+// ListCache, and the database when deleteDb is set
+func PlanPurge(database *sql.DB, dbPath string, opts types.Options, deleteDb bool) types.Plan {
+	var names []string
+	for _, font := range db.GetInstalledFonts(database) {
+		names = append(names, font.Name)
+	}
+	plan := PlanUninstall(database, names, opts)
+
+	for _, entry := range ListCache(database, opts.DownloadPath) {
+		plan.Add(cacheAction(entry))
+	}
+
+	if deleteDb {
+		plan.Add(types.Action{Kind: types.ActionDeleteDb, Path: db.GetDbFile(dbPath)})
+	}
+
+	return plan
+}
+
+func HandlePurge(cmd *types.PurgeCmd, database *sql.DB, dbPath string, opts types.Options) error {
+	plan := PlanPurge(database, dbPath, opts, cmd.Db)
+	if opts.DryRun {
+		PrintPlan(plan, opts)
+		return nil
+	}
+	if plan.IsEmpty() {
+		fmt.Println("Nothing to purge")
+		return nil
+	}
+
+	removedFonts, err := ExecutePlan(database, plan, opts)
+	printPurgeSummary(plan, removedFonts)
+
+	return err
+}
+
+func printPurgeSummary(plan types.Plan, removedFonts []string) {
+	isRemoved := make(map[string]bool)
+	for _, name := range removedFonts {
+		isRemoved[name] = true
+	}
+
+	var registryValues, files, archives int
+	var archivesSize int64
+	dbDeleted := false
+	for _, action := range plan.Actions {
+		_, statErr := os.Stat(action.Path)
+		isGone := errors.Is(statErr, os.ErrNotExist)
+		switch {
+		case action.Kind == types.ActionRemoveRegistryValue && isRemoved[action.Font.Name]:
+			registryValues++
+		case action.Kind == types.ActionRemoveFile && isGone:
+			files++
+		case action.Kind == types.ActionDeleteArchive && isGone:
+			archives++
+			archivesSize += action.Size
+		case action.Kind == types.ActionDeleteDb && isGone:
+			dbDeleted = true
+		}
+	}
+
+	fmt.Printf("Removed %v font(s) with %v file(s) and %v registry value(s)\n", len(removedFonts), files, registryValues)
+	fmt.Printf("Deleted %v cached archive(s), %v\n", archives, humanize.Bytes(uint64(archivesSize)))
+	if dbDeleted {
+		fmt.Println("Deleted the database")
+	}
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dustin/go-humanize"
+	"github.com/getnf/winferior/internal/db"
+	"github.com/getnf/winferior/internal/types"
+)
+
+// FontUsage measures the files of an installed font in the extract path, font files of
+// variants opts does not want are reported as unused
+func FontUsage(font types.Font, opts types.Options) types.FontUsage {
+	usage := types.FontUsage{
+		Name:        font.Name,
+		Version:     font.InstalledVersion,
+		ArchiveSize: font.Size,
+		Variants:    make(map[string]int64),
+	}
+
+	fontPath := filepath.Join(opts.ExtractPath, font.Name)
+	files, err := os.ReadDir(fontPath)
+	if err != nil {
+		return usage
+	}
+	for _, file := range files {
+		info, err := file.Info()
+		if err != nil || file.IsDir() {
+			continue
+		}
+		usage.Files++
+		usage.Size += info.Size()
+
+		variant := types.FontVariant(file.Name())
+		if variant == "" {
+			continue
+		}
+		usage.Variants[variant] += info.Size()
+		if !opts.WantsVariant(variant) {
+			usage.Unused = append(usage.Unused, filepath.Join(fontPath, file.Name()))
+			usage.UnusedSize += info.Size()
+		}
+	}
+
+	return usage
+}
+
+// DiskUsage returns the usage of the named installed fonts, or of all installed fonts
+// when names is empty. Archive sizes come from the catalog.
+func DiskUsage(database *sql.DB, data types.NerdFonts, names []string, opts types.Options, correct CorrectFunc) ([]types.FontUsage, error) {
+	var usages []types.FontUsage
+	var fonts []types.Font
+	if len(names) == 0 {
+		fonts = db.GetInstalledFonts(database)
+	} else {
+		names, err := ResolveFontNames(names, installedFontNames(database), FontAliases(database), "is not installed", correct)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			fonts = append(fonts, db.GetInstalledFont(database, types.Font{Name: name}))
+		}
+	}
+
+	for _, font := range fonts {
+		if data.HasFont(font.Name) {
+			font.Size = data.GetFont(font.Name).Size
+		}
+		usages = append(usages, FontUsage(font, opts))
+	}
+
+	return usages, nil
+}
+
+// FontsWithSize adds the installed size to the installed fonts of fonts
+func FontsWithSize(fonts []types.Font, opts types.Options) []types.Font {
+	var results []types.Font
+	for _, font := range fonts {
+		if font.InstalledVersion != "-" {
+			font.InstalledSize = FontUsage(font, opts).Size
+		}
+		results = append(results, font)
+	}
+	return results
+}
+
+// PlanReclaim plans removing the unused font files of usages along with their registry values
+func PlanReclaim(usages []types.FontUsage) types.Plan {
+	var plan types.Plan
+	for _, usage := range usages {
+		font := types.Font{Name: usage.Name}
+		for _, file := range usage.Unused {
+			plan.Add(types.Action{Kind: types.ActionRemoveRegistryValue, Font: font, Path: filepath.Base(file)})
+		}
+		for _, file := range usage.Unused {
+			size := int64(0)
+			if info, err := os.Stat(file); err == nil {
+				size = info.Size()
+			}
+			plan.Add(types.Action{Kind: types.ActionRemoveFile, Font: font, Path: file, Size: size})
+		}
+	}
+	return plan
+}
+
+func formatSize(size int64) string {
+	if size == 0 {
+		return "-"
+	}
+	return humanize.Bytes(uint64(size))
+}
+
+func printUsage(usages []types.FontUsage) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 4, '\t', tabwriter.AlignRight)
+	fmt.Fprintln(writer, "Name:\tVersion:\tDefault:\tMono:\tPropo:\tInstalled:\tArchive:")
+
+	var size, archiveSize int64
+	for _, usage := range usages {
+		fmt.Fprintln(writer, usage.Name, "\t", usage.Version,
+			"\t", formatSize(usage.GetVariantSize(types.VariantDefault)),
+			"\t", formatSize(usage.GetVariantSize(types.VariantMono)),
+			"\t", formatSize(usage.GetVariantSize(types.VariantPropo)),
+			"\t", formatSize(usage.Size), "\t", formatSize(usage.ArchiveSize))
+		size += usage.Size
+		archiveSize += usage.ArchiveSize
+	}
+	writer.Flush()
+
+	fmt.Printf("%v font(s), %v installed, %v of archives\n", len(usages), humanize.Bytes(uint64(size)), humanize.Bytes(uint64(archiveSize)))
+}
+
+// HandleDu prints the disk usage of installed fonts and reclaims the space of the variants
+// which are not kept when asked to
+func HandleDu(cmd *types.DuCmd, database *sql.DB, data types.NerdFonts, opts types.Options, correct CorrectFunc) error {
+	if len(cmd.Keep) > 0 {
+		for _, variant := range cmd.Keep {
+			if !slices.Contains(types.Variants, variant) {
+				return fmt.Errorf("unknown variant %q, expected one of %v", variant, strings.Join(types.Variants, ", "))
+			}
+		}
+		opts.Variants = cmd.Keep
+	}
+
+	usages, err := DiskUsage(database, data, cmd.Fonts, opts, correct)
+	if err != nil {
+		return err
+	}
+	if len(usages) == 0 {
+		fmt.Println("No fonts have been installed yet")
+		return nil
+	}
+
+	var unusedSize int64
+	for _, usage := range usages {
+		unusedSize += usage.UnusedSize
+	}
+
+	if !cmd.Reclaim {
+		printUsage(usages)
+		if unusedSize > 0 {
+			command := "winferior du --reclaim"
+			for _, name := range cmd.Fonts {
+				if strings.Contains(name, " ") {
+					name = strconv.Quote(name)
+				}
+				command += " " + name
+			}
+			if len(cmd.Keep) > 0 {
+				command += " --keep " + strings.Join(cmd.Keep, " ")
+			}
+			fmt.Printf("%v can be reclaimed by keeping only the %v variants, run %v to do so\n", humanize.Bytes(uint64(unusedSize)), strings.Join(opts.Variants, ", "), command)
+		}
+		return nil
+	}
+
+	if len(opts.Variants) == 0 {
+		return fmt.Errorf("no variants to keep, choose them with --keep or the variants setting")
+	}
+	plan := PlanReclaim(usages)
+	if plan.IsEmpty() {
+		fmt.Println("Only the kept variants are installed, nothing to reclaim")
+		return nil
+	}
+
+	_, err = RunPlan(database, plan, opts)
+	if err == nil && !opts.DryRun {
+		fmt.Printf("Reclaimed %v\n", humanize.Bytes(uint64(plan.GetSize(types.ActionRemoveFile))))
+	}
+
+	return err
+}
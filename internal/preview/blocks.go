@@ -0,0 +1,66 @@
+package preview
+
+import (
+	"image"
+	"image/draw"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Block drawings
+
+// the size glyphs are rasterized at before they are scaled down to the cells
+const blocksSize = 128
+
+// halfBlocks are indexed by whether the upper and the lower half of a cell are set
+var halfBlocks = [2][2]string{{" ", "▄"}, {"▀", "█"}}
+
+// Blocks draws text rasterized with face in columns by rows cells of half block
+// characters, every cell holds two pixels on top of each other which are about square.
+// It works in every terminal, unlike TerminalImage, and fits into the text of a layout.
+func Blocks(face font.Face, text string, columns int, rows int) string {
+	bounds, _ := font.BoundString(face, text)
+	rect := image.Rect(bounds.Min.X.Floor(), bounds.Min.Y.Floor(), bounds.Max.X.Ceil(), bounds.Max.Y.Ceil())
+	lines := make([]string, rows)
+	if rect.Empty() || columns <= 0 || rows <= 0 {
+		for i := range lines {
+			lines[i] = strings.Repeat(" ", max(columns, 0))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	glyph := image.NewAlpha(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	drawer := font.Drawer{Dst: glyph, Src: image.Opaque, Face: face, Dot: fixed.P(-rect.Min.X, -rect.Min.Y)}
+	drawer.DrawString(text)
+
+	// scale to fit keeping the aspect ratio and center on the canvas
+	width, height := columns, rows*2
+	scale := min(float64(width)/float64(rect.Dx()), float64(height)/float64(rect.Dy()))
+	target := image.Rect(0, 0, max(int(float64(rect.Dx())*scale), 1), max(int(float64(rect.Dy())*scale), 1))
+	target = target.Add(image.Pt((width-target.Dx())/2, (height-target.Dy())/2))
+	canvas := image.NewAlpha(image.Rect(0, 0, width, height))
+	xdraw.ApproxBiLinear.Scale(canvas, target, glyph, glyph.Bounds(), draw.Src, nil)
+
+	isSet := func(x int, y int) int {
+		if canvas.AlphaAt(x, y).A >= 0x80 {
+			return 1
+		}
+		return 0
+	}
+	for row := range lines {
+		var line strings.Builder
+		for x := 0; x < width; x++ {
+			line.WriteString(halfBlocks[isSet(x, row*2)][isSet(x, row*2+1)])
+		}
+		lines[row] = line.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// NewBlocksFace returns a face of fontData to draw Blocks with
+func NewBlocksFace(fontData []byte) (font.Face, error) {
+	return NewFace(fontData, blocksSize)
+}
@@ -0,0 +1,132 @@
+package preview
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+var (
+	DefaultForeground = color.RGBA{0xcd, 0xd6, 0xf4, 0xff}
+	DefaultBackground = color.RGBA{0x1e, 0x1e, 0x2e, 0xff}
+)
+
+const DefaultSize = 32
+
+// Sample is the text rendered in a preview, a size of zero uses DefaultSize and a padding
+// of zero half a line
+type Sample struct {
+	Lines      []string
+	Size       float64
+	Padding    int
+	Foreground color.Color
+	Background color.Color
+}
+
+// DefaultLines returns the sample text of a preview of the font name, covering letters,
+// digits, common ligatures, code and a few Nerd Fonts icons
+func DefaultLines(name string) []string {
+	return []string{
+		name,
+		"The quick brown fox jumps over the lazy dog",
+		"0123456789 Il1| O0 {}[]() -> => != <= >= ===",
+		`func main() { fmt.Println("hello, world") }`,
+		"\uf113 \ue725 \ue702 \uf07b \uf120 \ue70f \ue62b \uf0e7",
+	}
+}
+
+// ParseLines splits text given on the command line into lines, a literal \n separates lines too
+func ParseLines(text string) []string {
+	return strings.Split(strings.ReplaceAll(text, `\n`, "\n"), "\n")
+}
+
+func NewSample(lines []string, size float64) Sample {
+	return Sample{Lines: lines, Size: size, Foreground: DefaultForeground, Background: DefaultBackground}
+}
+
+// NewFace parses a TrueType or OpenType font and returns a face of size points at 72 dpi
+func NewFace(fontData []byte, size float64) (font.Face, error) {
+	parsed, err := opentype.Parse(fontData)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the font: %v", err)
+	}
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return opentype.NewFace(parsed, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+}
+
+// Render rasterizes the lines of sample with the font in fontData onto an image which
+// is just large enough to hold them
+func Render(fontData []byte, sample Sample) (*image.RGBA, error) {
+	face, err := NewFace(fontData, sample.Size)
+	if err != nil {
+		return nil, err
+	}
+	defer face.Close()
+
+	return RenderFace(face, sample), nil
+}
+
+// RenderFace rasterizes the lines of sample with face
+func RenderFace(face font.Face, sample Sample) *image.RGBA {
+	if sample.Foreground == nil {
+		sample.Foreground = DefaultForeground
+	}
+	if sample.Background == nil {
+		sample.Background = DefaultBackground
+	}
+
+	metrics := face.Metrics()
+	lineHeight := metrics.Height.Ceil()
+	padding := sample.Padding
+	if padding <= 0 {
+		padding = lineHeight / 2
+	}
+
+	width := 0
+	for _, line := range sample.Lines {
+		width = max(width, font.MeasureString(face, line).Ceil())
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width+2*padding, len(sample.Lines)*lineHeight+2*padding))
+	draw.Draw(img, img.Bounds(), image.NewUniform(sample.Background), image.Point{}, draw.Src)
+
+	drawer := font.Drawer{Dst: img, Src: image.NewUniform(sample.Foreground), Face: face}
+	for i, line := range sample.Lines {
+		drawer.Dot = fixed.P(padding, padding+i*lineHeight+metrics.Ascent.Ceil())
+		drawer.DrawString(line)
+	}
+
+	return img
+}
+
+// WritePng writes img to path, creating its directory if needed
+func WritePng(path string, img image.Image) error {
+	err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = png.Encode(file, img)
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+}
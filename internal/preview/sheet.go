@@ -0,0 +1,175 @@
+package preview
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// Comparison sheets
+
+// SheetFont is a font shown on a comparison sheet, FileName is the font file Data was read from
+type SheetFont struct {
+	Name     string
+	FileName string
+	Data     []byte
+}
+
+var labelColor = color.RGBA{0x93, 0x99, 0xb2, 0xff}
+
+// CompareLines returns the sample every font of a comparison sheet is rendered with: code,
+// ligatures and rows of icons
+func CompareLines() []string {
+	return []string{
+		"func fib(n int) int {",
+		"    if n < 2 { return n }",
+		"    return fib(n-1) + fib(n-2) // 0O Il1|",
+		"}",
+		"-> => <= >= != == === !== :: ||= && |> <| ++ -- /* */ www",
+		"\uf17a \uf17c \uf179 \uf09b \ue725 \uf120 \uf121 \uf07b",
+		"\ue73c \ue7a8 \ue626 \ue74e \ue718 \ue61e \ue739 \ue77f",
+	}
+}
+
+// RenderSheet stacks the samples of fonts, each below a label with its name
+func RenderSheet(fonts []SheetFont, sample Sample) (*image.RGBA, error) {
+	if sample.Background == nil {
+		sample.Background = DefaultBackground
+	}
+	if sample.Size <= 0 {
+		sample.Size = DefaultSize
+	}
+
+	labelFace, err := NewFace(goregular.TTF, sample.Size*0.6)
+	if err != nil {
+		return nil, err
+	}
+	defer labelFace.Close()
+
+	var blocks []image.Image
+	for _, font := range fonts {
+		face, err := NewFace(font.Data, sample.Size)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", font.FileName, err)
+		}
+		label := Sample{
+			Lines:      []string{fmt.Sprintf("%v (%v)", font.Name, font.FileName)},
+			Padding:    face.Metrics().Height.Ceil() / 2,
+			Foreground: labelColor,
+			Background: sample.Background,
+		}
+		blocks = append(blocks, RenderFace(labelFace, label), RenderFace(face, sample))
+		face.Close()
+	}
+
+	width, height := 0, 0
+	for _, block := range blocks {
+		width = max(width, block.Bounds().Dx())
+		height += block.Bounds().Dy()
+	}
+
+	sheet := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(sheet, sheet.Bounds(), image.NewUniform(sample.Background), image.Point{}, draw.Src)
+	y := 0
+	for _, block := range blocks {
+		draw.Draw(sheet, block.Bounds().Add(image.Pt(0, y)), block, block.Bounds().Min, draw.Src)
+		y += block.Bounds().Dy()
+	}
+
+	return sheet, nil
+}
+
+func WriteSheetPng(path string, fonts []SheetFont, sample Sample) error {
+	sheet, err := RenderSheet(fonts, sample)
+	if err != nil {
+		return err
+	}
+	return WritePng(path, sheet)
+}
+
+var sheetTemplate = template.Must(template.New("sheet").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Nerd Fonts comparison</title>
+<style>
+{{range .Fonts}}{{.FontFace}}
+{{end}}body { background: {{.Background}}; color: {{.Foreground}}; margin: 2em; }
+h2 { font: 1em sans-serif; color: {{.Label}}; margin: 2em 0 0.5em; }
+pre { font-size: {{.Size}}px; line-height: 1.4; margin: 0; }
+</style>
+</head>
+<body>
+{{range .Fonts}}<h2>{{.Name}} ({{.FileName}})</h2>
+<pre style="font-family: '{{.Family}}', monospace">{{$.Text}}</pre>
+{{end}}</body>
+</html>
+`))
+
+func cssColor(c color.Color) template.CSS {
+	r, g, b, _ := c.RGBA()
+	return template.CSS(fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8))
+}
+
+// WriteSheetHtml writes a page showing the sample in each of fonts, the fonts are embedded
+// so the page works offline and without installing them
+func WriteSheetHtml(path string, fonts []SheetFont, sample Sample) error {
+	if sample.Foreground == nil {
+		sample.Foreground = DefaultForeground
+	}
+	if sample.Background == nil {
+		sample.Background = DefaultBackground
+	}
+	if sample.Size <= 0 {
+		sample.Size = DefaultSize
+	}
+
+	type sheetFont struct {
+		SheetFont
+		Family   string
+		FontFace template.CSS
+	}
+	var sheetFonts []sheetFont
+	for i, font := range fonts {
+		family := fmt.Sprintf("font%d", i)
+		format := "truetype"
+		if strings.EqualFold(filepath.Ext(font.FileName), ".otf") {
+			format = "opentype"
+		}
+		fontFace := fmt.Sprintf("@font-face { font-family: '%v'; src: url(data:font/%v;base64,%v) format('%v'); }",
+			family, strings.TrimPrefix(strings.ToLower(filepath.Ext(font.FileName)), "."), base64.StdEncoding.EncodeToString(font.Data), format)
+		sheetFonts = append(sheetFonts, sheetFont{SheetFont: font, Family: family, FontFace: template.CSS(fontFace)})
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = sheetTemplate.Execute(file, map[string]any{
+		"Fonts":      sheetFonts,
+		"Text":       strings.Join(sample.Lines, "\n"),
+		"Size":       sample.Size,
+		"Foreground": cssColor(sample.Foreground),
+		"Background": cssColor(sample.Background),
+		"Label":      cssColor(labelColor),
+	})
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,211 @@
+package preview
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/png"
+	"os"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// Terminal graphics
+
+type Protocol string
+
+const (
+	ProtocolNone   Protocol = "none"
+	ProtocolKitty  Protocol = "kitty"
+	ProtocolITerm2 Protocol = "iterm2"
+	ProtocolSixel  Protocol = "sixel"
+)
+
+// cell size assumed when sizing images for sixel, which has no notion of cells
+const (
+	CellWidth  = 10
+	CellHeight = 20
+)
+
+// DetectProtocol guesses the graphics protocol of the terminal from its environment,
+// WINFERIOR_GRAPHICS overrides the guess. Inside tmux images are not shown as they would
+// need to be passed through.
+func DetectProtocol() Protocol {
+	switch protocol := Protocol(strings.ToLower(os.Getenv("WINFERIOR_GRAPHICS"))); protocol {
+	case ProtocolNone, ProtocolKitty, ProtocolITerm2, ProtocolSixel:
+		return protocol
+	}
+
+	term := os.Getenv("TERM")
+	termProgram := os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("TMUX") != "":
+		return ProtocolNone
+	case term == "xterm-kitty" || os.Getenv("KITTY_WINDOW_ID") != "" || termProgram == "ghostty":
+		return ProtocolKitty
+	case termProgram == "iTerm.app" || termProgram == "WezTerm":
+		return ProtocolITerm2
+	case os.Getenv("WT_SESSION") != "" || strings.Contains(term, "sixel") || term == "foot" || strings.HasPrefix(term, "mlterm"):
+		return ProtocolSixel
+	default:
+		return ProtocolNone
+	}
+}
+
+// Fit scales img down to fit into width by height pixels if needed and places it on a
+// canvas of exactly that size, so that a smaller image fully covers a larger one
+func Fit(img image.Image, width int, height int, background color.Color) *image.RGBA {
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+
+	bounds := img.Bounds()
+	scale := min(1, float64(width)/float64(bounds.Dx()), float64(height)/float64(bounds.Dy()))
+	target := image.Rect(0, 0, int(float64(bounds.Dx())*scale), int(float64(bounds.Dy())*scale))
+	target = target.Add(image.Pt(0, (height-target.Dy())/2))
+	xdraw.CatmullRom.Scale(canvas, target, img, bounds, draw.Src, nil)
+
+	return canvas
+}
+
+// TerminalImage returns the escape sequence showing img in columns by rows cells at the
+// cursor, the cursor itself does not move
+func TerminalImage(img image.Image, protocol Protocol, columns int, rows int) (string, error) {
+	switch protocol {
+	case ProtocolKitty:
+		encoded, err := encodePng(img)
+		if err != nil {
+			return "", err
+		}
+		return kittyImage(encoded, columns, rows), nil
+	case ProtocolITerm2:
+		encoded, err := encodePng(img)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("\x1b7\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%v\a\x1b8", len(encoded)*3/4, columns, rows, encoded), nil
+	case ProtocolSixel:
+		return "\x1b7" + sixelImage(img) + "\x1b8", nil
+	default:
+		return "", nil
+	}
+}
+
+// ClearImages removes the images placed with protocol, only kitty keeps images apart from
+// the text, the others are overwritten by it
+func ClearImages(protocol Protocol) string {
+	if protocol == ProtocolKitty {
+		return "\x1b_Ga=d,q=2\x1b\\"
+	}
+	return ""
+}
+
+func encodePng(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, img)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func kittyImage(encoded string, columns int, rows int) string {
+	var sequence strings.Builder
+	sequence.WriteString(ClearImages(ProtocolKitty))
+	for i := 0; i < len(encoded); i += 4096 {
+		chunk := encoded[i:min(i+4096, len(encoded))]
+		more := 0
+		if i+4096 < len(encoded) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&sequence, "\x1b_Ga=T,f=100,q=2,C=1,c=%d,r=%d,m=%d;%v\x1b\\", columns, rows, more, chunk)
+		} else {
+			fmt.Fprintf(&sequence, "\x1b_Gm=%d;%v\x1b\\", more, chunk)
+		}
+	}
+	return sequence.String()
+}
+
+// sixelImage encodes img as sixels. Previews are anti-aliased text in a single color,
+// their colors fit into a palette of their own; other images are dithered to the Plan 9 palette.
+func sixelImage(img image.Image) string {
+	bounds := img.Bounds()
+
+	var colors color.Palette
+	seen := make(map[color.Color]bool)
+	for y := bounds.Min.Y; y < bounds.Max.Y && len(colors) <= 256; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X && len(colors) <= 256; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y))
+			if !seen[c] {
+				seen[c] = true
+				colors = append(colors, c)
+			}
+		}
+	}
+	paletted := image.NewPaletted(bounds, colors)
+	if len(colors) > 256 {
+		paletted = image.NewPaletted(bounds, palette.Plan9)
+		draw.FloydSteinberg.Draw(paletted, bounds, img, bounds.Min)
+	} else {
+		draw.Draw(paletted, bounds, img, bounds.Min, draw.Src)
+	}
+
+	var sixel strings.Builder
+	fmt.Fprintf(&sixel, "\x1bPq\"1;1;%d;%d", bounds.Dx(), bounds.Dy())
+	for i, c := range paletted.Palette {
+		r, g, b, _ := c.RGBA()
+		fmt.Fprintf(&sixel, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, b*100/0xffff)
+	}
+
+	for top := bounds.Min.Y; top < bounds.Max.Y; top += 6 {
+		used := make(map[uint8]bool)
+		for y := top; y < min(top+6, bounds.Max.Y); y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				used[paletted.ColorIndexAt(x, y)] = true
+			}
+		}
+		for index := range paletted.Palette {
+			if !used[uint8(index)] {
+				continue
+			}
+			fmt.Fprintf(&sixel, "#%d", index)
+			var run byte
+			count := 0
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				bits := byte(0)
+				for bit := 0; bit < 6 && top+bit < bounds.Max.Y; bit++ {
+					if paletted.ColorIndexAt(x, top+bit) == uint8(index) {
+						bits |= 1 << bit
+					}
+				}
+				if char := '?' + bits; char == run {
+					count++
+				} else {
+					writeSixelRun(&sixel, run, count)
+					run, count = char, 1
+				}
+			}
+			writeSixelRun(&sixel, run, count)
+			sixel.WriteByte('$')
+		}
+		sixel.WriteByte('-')
+	}
+	sixel.WriteString("\x1b\\")
+
+	return sixel.String()
+}
+
+func writeSixelRun(sixel *strings.Builder, char byte, count int) {
+	switch {
+	case count == 0:
+	case count > 3:
+		fmt.Fprintf(sixel, "!%d%c", count, char)
+	default:
+		sixel.WriteString(strings.Repeat(string(char), count))
+	}
+}
@@ -0,0 +1,162 @@
+package theme
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	catppuccin "github.com/catppuccin/go"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	PresetWinferior    = "winferior"
+	PresetCatppuccin   = "catppuccin"
+	PresetHighContrast = "high-contrast"
+	PresetNoColor      = "no-color"
+)
+
+var Presets = []string{PresetWinferior, PresetCatppuccin, PresetHighContrast, PresetNoColor}
+
+// Theme holds the colors and selectors shared by the TUI and the tables printed by
+// the commands
+type Theme struct {
+	Name string
+	// Accent colors titles, the cursor and outdated fonts
+	Accent lipgloss.TerminalColor
+	// Selected colors selected options and fonts to install
+	Selected lipgloss.TerminalColor
+	// Danger colors fonts to uninstall and errors
+	Danger lipgloss.TerminalColor
+	// Border colors borders and inactive tabs
+	Border lipgloss.TerminalColor
+	// Muted colors help texts, labels and installed fonts
+	Muted lipgloss.TerminalColor
+	// Bold makes highlighted text bold, for themes which can not rely on colors alone
+	Bold bool
+
+	Cursor           string
+	SelectedPrefix   string
+	UnselectedPrefix string
+}
+
+func Winferior() Theme {
+	return Theme{
+		Name:             PresetWinferior,
+		Accent:           lipgloss.Color("3"),
+		Selected:         lipgloss.Color("2"),
+		Danger:           lipgloss.Color("1"),
+		Border:           lipgloss.Color("7"),
+		Muted:            lipgloss.Color("8"),
+		Cursor:           "> ",
+		SelectedPrefix:   "[•] ",
+		UnselectedPrefix: "[ ] ",
+	}
+}
+
+func Catppuccin() Theme {
+	adaptive := func(latte catppuccin.Color, mocha catppuccin.Color) lipgloss.AdaptiveColor {
+		return lipgloss.AdaptiveColor{Light: latte.Hex, Dark: mocha.Hex}
+	}
+	light, dark := catppuccin.Latte, catppuccin.Mocha
+
+	t := Winferior()
+	t.Name = PresetCatppuccin
+	t.Accent = adaptive(light.Mauve(), dark.Mauve())
+	t.Selected = adaptive(light.Green(), dark.Green())
+	t.Danger = adaptive(light.Red(), dark.Red())
+	t.Border = adaptive(light.Surface2(), dark.Surface2())
+	t.Muted = adaptive(light.Overlay1(), dark.Overlay1())
+	return t
+}
+
+func HighContrast() Theme {
+	t := Winferior()
+	t.Name = PresetHighContrast
+	t.Accent = lipgloss.Color("11")
+	t.Selected = lipgloss.Color("10")
+	t.Danger = lipgloss.Color("9")
+	t.Border = lipgloss.Color("15")
+	t.Muted = lipgloss.Color("7")
+	t.Bold = true
+	return t
+}
+
+func NoColor() Theme {
+	t := Winferior()
+	t.Name = PresetNoColor
+	t.Accent = lipgloss.NoColor{}
+	t.Selected = lipgloss.NoColor{}
+	t.Danger = lipgloss.NoColor{}
+	t.Border = lipgloss.NoColor{}
+	t.Muted = lipgloss.NoColor{}
+	t.Bold = true
+	return t
+}
+
+func Preset(name string) (Theme, error) {
+	switch name {
+	case PresetWinferior, "":
+		return Winferior(), nil
+	case PresetCatppuccin:
+		return Catppuccin(), nil
+	case PresetHighContrast:
+		return HighContrast(), nil
+	case PresetNoColor:
+		return NoColor(), nil
+	}
+	return Theme{}, fmt.Errorf("unknown theme %q, valid themes are %v", name, strings.Join(Presets, ", "))
+}
+
+// IsNoColorSet reports whether the user opted out of colors, see https://no-color.org
+func IsNoColorSet() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// IsColor reports whether color is an ANSI color between 0 and 255 or a hex color like #f5c2e7
+func IsColor(color string) bool {
+	if hexColor.MatchString(color) {
+		return true
+	}
+	n, err := strconv.Atoi(color)
+	return err == nil && n >= 0 && n <= 255
+}
+
+var current = Winferior()
+
+func Current() Theme {
+	return current
+}
+
+func Set(t Theme) {
+	current = t
+}
+
+// HasColors reports whether the theme uses colors at all
+func (t Theme) HasColors() bool {
+	return t.Name != PresetNoColor
+}
+
+func (t Theme) style(color lipgloss.TerminalColor) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(color)
+}
+
+func (t Theme) AccentStyle() lipgloss.Style {
+	return t.style(t.Accent).Bold(t.Bold)
+}
+
+func (t Theme) SelectedStyle() lipgloss.Style {
+	return t.style(t.Selected).Bold(t.Bold)
+}
+
+func (t Theme) DangerStyle() lipgloss.Style {
+	return t.style(t.Danger).Bold(t.Bold)
+}
+
+func (t Theme) MutedStyle() lipgloss.Style {
+	return t.style(t.Muted)
+}
@@ -0,0 +1,570 @@
+package tui
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/getnf/winferior/internal/db"
+	"github.com/getnf/winferior/internal/handlers"
+	"github.com/getnf/winferior/internal/preview"
+	"github.com/getnf/winferior/internal/theme"
+	"github.com/getnf/winferior/internal/types"
+	"github.com/muesli/termenv"
+)
+
+type dashboardTab int
+
+const (
+	tabBrowse dashboardTab = iota
+	tabInstalled
+	tabUpdates
+	tabGlyphs
+)
+
+var dashboardTabs = []string{"Browse", "Installed", "Updates", "Glyphs"}
+
+type dashboardState int
+
+const (
+	dashboardMarking dashboardState = iota
+	dashboardApplying
+	dashboardDone
+)
+
+var (
+	dashboardTabStyle       lipgloss.Style
+	dashboardActiveTabStyle lipgloss.Style
+	installMarkStyle        lipgloss.Style
+	uninstallMarkStyle      lipgloss.Style
+)
+
+func setDashboardStyles(t theme.Theme) {
+	dashboardTabStyle = lipgloss.NewStyle().Padding(0, 1).Foreground(t.Border)
+	dashboardActiveTabStyle = dashboardTabStyle.Reverse(true).Foreground(t.Accent)
+	installMarkStyle = t.SelectedStyle()
+	uninstallMarkStyle = t.DangerStyle()
+}
+
+// fontList is a filterable list of fonts in which fonts can be marked
+type fontList struct {
+	fonts    []types.Font
+	filtered []types.Font
+	marked   map[string]bool
+	filter   textinput.Model
+	aliases  types.FontAliases
+	cursor   int
+	offset   int
+	height   int
+}
+
+func newFontList(fonts []types.Font, aliases types.FontAliases) fontList {
+	filter := textinput.New()
+	filter.Prompt = "/ "
+	filter.Placeholder = "filter fonts"
+	return fontList{fonts: fonts, filtered: fonts, marked: make(map[string]bool), filter: filter, aliases: aliases, height: 20}
+}
+
+func (l fontList) rows() int {
+	return max(l.height-2, 1)
+}
+
+func (l fontList) highlighted() (types.Font, bool) {
+	if l.cursor < 0 || l.cursor >= len(l.filtered) {
+		return types.Font{}, false
+	}
+	return l.filtered[l.cursor], true
+}
+
+func (l fontList) markedFonts() []types.Font {
+	var fonts []types.Font
+	for _, font := range l.fonts {
+		if l.marked[font.Name] {
+			fonts = append(fonts, font)
+		}
+	}
+	return fonts
+}
+
+func (l fontList) update(msg tea.KeyMsg) (fontList, tea.Cmd) {
+	if l.filter.Focused() {
+		switch msg.String() {
+		case "esc", "enter":
+			l.filter.Blur()
+			return l, nil
+		}
+		var cmd tea.Cmd
+		l.filter, cmd = l.filter.Update(msg)
+		l.filtered = filterFonts(l.fonts, l.filter.Value(), l.aliases)
+		l.cursor = 0
+		l.offset = 0
+		return l, cmd
+	}
+
+	switch {
+	case key.Matches(msg, keys.Filter):
+		return l, l.filter.Focus()
+	case msg.String() == "esc":
+		l.filter.SetValue("")
+		l.filtered = l.fonts
+	case key.Matches(msg, keys.Toggle):
+		if font, ok := l.highlighted(); ok {
+			l.marked[font.Name] = !l.marked[font.Name]
+		}
+	case msg.String() == "A":
+		allMarked := len(l.markedFonts()) == len(l.fonts)
+		for _, font := range l.fonts {
+			l.marked[font.Name] = !allMarked
+		}
+	case key.Matches(msg, keys.Up):
+		l.cursor--
+	case key.Matches(msg, keys.Down):
+		l.cursor++
+	case key.Matches(msg, keys.PageUp):
+		l.cursor -= l.rows()
+	case key.Matches(msg, keys.PageDown):
+		l.cursor += l.rows()
+	case key.Matches(msg, keys.Top):
+		l.cursor = 0
+	case key.Matches(msg, keys.Bottom):
+		l.cursor = len(l.filtered) - 1
+	}
+
+	l.cursor = min(max(l.cursor, 0), max(len(l.filtered)-1, 0))
+	if l.cursor < l.offset {
+		l.offset = l.cursor
+	} else if l.cursor >= l.offset+l.rows() {
+		l.offset = l.cursor - l.rows() + 1
+	}
+	return l, nil
+}
+
+func (l fontList) view(empty string, mark string, markStyle lipgloss.Style, describe func(types.Font) string) string {
+	lines := []string{l.filter.View()}
+	for i := l.offset; i < min(l.offset+l.rows(), len(l.filtered)); i++ {
+		font := l.filtered[i]
+		cursor := strings.Repeat(" ", lipgloss.Width(theme.Current().Cursor))
+		if i == l.cursor {
+			cursor = selectorCursorStyle.Render(theme.Current().Cursor)
+		}
+		line := theme.Current().UnselectedPrefix + describe(font)
+		if l.marked[font.Name] {
+			line = markStyle.Render(fmt.Sprintf("[%v] %v", mark, describe(font)))
+		}
+		lines = append(lines, cursor+line)
+	}
+	if len(l.fonts) == 0 {
+		lines = append(lines, "  "+empty)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// dashboardJob is the plan of a single font, jobs run one after another to show progress
+type dashboardJob struct {
+	verb string
+	name string
+	plan types.Plan
+}
+
+type jobDoneMsg struct {
+	err error
+}
+
+type glyphsLoadedMsg struct {
+	glyphs []types.Glyph
+	err    error
+}
+
+// dashboard lets fonts be marked for install, uninstall and update across tabs and applies
+// all of them at once
+type dashboard struct {
+	database *sql.DB
+	data     types.NerdFonts
+	source   *types.Source
+	opts     types.Options
+
+	tab       dashboardTab
+	browse    installSelector
+	installed fontList
+	updates   fontList
+	glyphs    *glyphBrowser
+	glyphsErr error
+	width     int
+	height    int
+
+	state    dashboardState
+	jobs     []dashboardJob
+	doneJobs int
+	errs     []error
+	spinner  spinner.Model
+	progress progress.Model
+	dryRun   types.Plan
+}
+
+func newDashboard(database *sql.DB, data types.NerdFonts, source *types.Source, opts types.Options, tab dashboardTab) dashboard {
+	fonts := handlers.FontsWithMeta(database, handlers.FontsWithVersion(database, data.GetFonts(), data.GetVersion()))
+	aliases := handlers.FontAliases(database)
+	browse := newInstallSelector(data, fonts, aliases, source, opts)
+	browse.embedded = true
+
+	installed := db.GetInstalledFonts(database)
+	return dashboard{
+		database:  database,
+		data:      data,
+		source:    source,
+		opts:      opts,
+		tab:       tab,
+		browse:    browse,
+		installed: newFontList(installed, aliases),
+		updates:   newFontList(handlers.OutdatedFonts(database), aliases),
+		width:     80,
+		height:    24,
+		spinner:   spinner.New(spinner.WithSpinner(spinner.Dot)),
+		progress:  newProgress(),
+	}
+}
+
+func (m dashboard) loadGlyphs() tea.Msg {
+	glyphs, err := handlers.Glyphs(m.database, m.source, m.data.GetVersion())
+	return glyphsLoadedMsg{glyphs: glyphs, err: err}
+}
+
+func (m dashboard) markedCount() int {
+	return len(m.browse.selectedFonts()) + len(m.installed.markedFonts()) + len(m.updates.markedFonts())
+}
+
+// planJobs turns the marks into jobs, uninstalls run first so that a font can be reinstalled
+func (m dashboard) planJobs() []dashboardJob {
+	var jobs []dashboardJob
+	for _, font := range m.installed.markedFonts() {
+		jobs = append(jobs, dashboardJob{verb: "Uninstalling", name: font.Name, plan: handlers.PlanUninstall(m.database, []string{font.Name}, m.opts)})
+	}
+	updating := make(map[string]bool)
+	for _, font := range m.updates.markedFonts() {
+		updating[font.Name] = true
+		jobs = append(jobs, dashboardJob{verb: "Updating", name: font.Name, plan: handlers.PlanUpdate(m.database, []types.Font{font}, m.source, m.opts)})
+	}
+	// installed fonts selected in the catalog get reinstalled or updated, never added twice
+	// and never next to an update of the same font
+	for _, font := range m.browse.fonts {
+		if !m.browse.selected[font.Name] || updating[font.Name] {
+			continue
+		}
+		plan := handlers.PlanInstall(m.database, []types.Font{m.data.GetFont(font.Name)}, m.data.GetVersion(), m.opts.Channel, m.source, m.opts)
+		jobs = append(jobs, dashboardJob{verb: installVerb(font), name: font.Name, plan: plan})
+	}
+	return jobs
+}
+
+func (m dashboard) runJob(i int) tea.Cmd {
+	job := m.jobs[i]
+	return func() tea.Msg {
+		_, err := handlers.ExecutePlan(m.database, job.plan, m.opts)
+		return jobDoneMsg{err: err}
+	}
+}
+
+// apply starts running the jobs, a dry run collects their plans and quits instead
+func (m dashboard) apply() (dashboard, tea.Cmd) {
+	m.jobs = m.planJobs()
+	if len(m.jobs) == 0 {
+		return m, nil
+	}
+	if m.opts.DryRun {
+		for _, job := range m.jobs {
+			m.dryRun.Append(job.plan)
+		}
+		return m, tea.Quit
+	}
+	m.state = dashboardApplying
+	return m, tea.Batch(m.spinner.Tick, m.runJob(0))
+}
+
+func (m dashboard) resize(width int, height int) dashboard {
+	m.width = width
+	m.height = height
+	bodyHeight := max(height-2, 5)
+
+	model, _ := m.browse.Update(tea.WindowSizeMsg{Width: width, Height: bodyHeight})
+	m.browse = model.(installSelector)
+	m.installed.height = bodyHeight
+	m.updates.height = bodyHeight
+	if m.glyphs != nil {
+		model, _ := m.glyphs.Update(tea.WindowSizeMsg{Width: width, Height: bodyHeight})
+		glyphs := model.(glyphBrowser)
+		m.glyphs = &glyphs
+	}
+	m.progress.Width = min(width-4, 60)
+	return m
+}
+
+func (m dashboard) filtering() bool {
+	switch m.tab {
+	case tabBrowse:
+		return m.browse.filter.Focused()
+	case tabInstalled:
+		return m.installed.filter.Focused()
+	case tabUpdates:
+		return m.updates.filter.Focused()
+	default:
+		return m.glyphs != nil && m.glyphs.filter.Focused()
+	}
+}
+
+func (m dashboard) switchTab(tab dashboardTab) (dashboard, tea.Cmd) {
+	m.tab = tab
+	if tab == tabGlyphs && m.glyphs == nil && m.glyphsErr == nil {
+		return m, m.loadGlyphs
+	}
+	return m, nil
+}
+
+func (m dashboard) Init() tea.Cmd {
+	cmd := m.browse.Init()
+	if m.tab == tabGlyphs {
+		return tea.Batch(cmd, m.loadGlyphs)
+	}
+	return cmd
+}
+
+func (m dashboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m = m.resize(msg.Width, msg.Height)
+		return m, m.browse.previewHighlighted()
+	case previewMsg:
+		model, cmd := m.browse.Update(msg)
+		m.browse = model.(installSelector)
+		return m, cmd
+	case glyphsLoadedMsg:
+		m.glyphsErr = msg.err
+		if len(msg.glyphs) > 0 {
+			glyphs := newGlyphBrowser(msg.glyphs, "", m.database, m.data, m.source, m.opts)
+			m.glyphs = &glyphs
+			m = m.resize(m.width, m.height)
+			return m, glyphs.Init()
+		}
+		return m, nil
+	case glyphFontMsg:
+		if m.glyphs != nil {
+			model, cmd := m.glyphs.Update(msg)
+			glyphs := model.(glyphBrowser)
+			m.glyphs = &glyphs
+			return m, cmd
+		}
+		return m, nil
+	case spinner.TickMsg:
+		if m.state != dashboardApplying {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	case jobDoneMsg:
+		m.doneJobs++
+		if msg.err != nil {
+			m.errs = append(m.errs, msg.err)
+		}
+		if m.doneJobs < len(m.jobs) {
+			return m, m.runJob(m.doneJobs)
+		}
+		m.state = dashboardDone
+		return m, nil
+	case tea.KeyMsg:
+		if m.state == dashboardApplying {
+			return m, nil
+		}
+		if m.state == dashboardDone {
+			if msg.String() == "esc" || key.Matches(msg, keys.Quit, keys.Submit) {
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+		if !m.filtering() {
+			if key.Matches(msg, keys.Quit) {
+				return m, tea.Quit
+			}
+			if key.Matches(msg, keys.Submit) && m.tab != tabGlyphs {
+				return m.apply()
+			}
+			switch msg.String() {
+			case "1", "2", "3", "4":
+				return m.switchTab(dashboardTab(msg.String()[0] - '1'))
+			case "]":
+				return m.switchTab((m.tab + 1) % dashboardTab(len(dashboardTabs)))
+			case "[":
+				return m.switchTab((m.tab + dashboardTab(len(dashboardTabs)) - 1) % dashboardTab(len(dashboardTabs)))
+			case "esc":
+				// the tabs quit on esc without a filter, the dashboard only quits on q
+				if m.tab == tabBrowse && m.browse.filter.Value() == "" ||
+					m.tab == tabGlyphs && (m.glyphs == nil || m.glyphs.filter.Value() == "") {
+					return m, nil
+				}
+			}
+		}
+
+		var cmd tea.Cmd
+		switch m.tab {
+		case tabBrowse:
+			var model tea.Model
+			model, cmd = m.browse.Update(msg)
+			m.browse = model.(installSelector)
+		case tabInstalled:
+			m.installed, cmd = m.installed.update(msg)
+		case tabUpdates:
+			m.updates, cmd = m.updates.update(msg)
+		case tabGlyphs:
+			if m.glyphs != nil {
+				model, glyphsCmd := m.glyphs.Update(msg)
+				glyphs := model.(glyphBrowser)
+				m.glyphs = &glyphs
+				cmd = glyphsCmd
+			}
+		}
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m dashboard) viewTabs() string {
+	counts := []int{len(m.browse.selectedFonts()), len(m.installed.markedFonts()), len(m.updates.markedFonts()), 0}
+	var tabs []string
+	for i, name := range dashboardTabs {
+		if counts[i] > 0 {
+			name = fmt.Sprintf("%v (%v)", name, counts[i])
+		}
+		if dashboardTab(i) == m.tab {
+			tabs = append(tabs, dashboardActiveTabStyle.Render(fmt.Sprintf("%v %v", i+1, name)))
+		} else {
+			tabs = append(tabs, dashboardTabStyle.Render(fmt.Sprintf("%v %v", i+1, name)))
+		}
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
+}
+
+func (m dashboard) viewTab() string {
+	switch m.tab {
+	case tabBrowse:
+		return m.browse.View()
+	case tabInstalled:
+		return m.installed.view("No fonts have been installed yet", "-", uninstallMarkStyle, func(font types.Font) string {
+			return fmt.Sprintf("%-30v %v (%v)", font.Name, font.InstalledVersion, font.Channel)
+		})
+	case tabUpdates:
+		return m.updates.view("All installed fonts are up to date", "↑", installMarkStyle, func(font types.Font) string {
+			return fmt.Sprintf("%-30v %v → %v", font.Name, font.InstalledVersion, font.AvailableVersion)
+		})
+	default:
+		if m.glyphs != nil {
+			return m.glyphs.View()
+		}
+		if m.glyphsErr != nil {
+			return m.glyphsErr.Error()
+		}
+		return m.spinner.View() + " Loading the glyph names…"
+	}
+}
+
+func (m dashboard) viewProgress() string {
+	var lines []string
+	for i, job := range m.jobs {
+		switch {
+		case i < m.doneJobs:
+			lines = append(lines, installMarkStyle.Render("✓ ")+job.verb+" "+job.name)
+		case i == m.doneJobs:
+			lines = append(lines, m.spinner.View()+" "+job.verb+" "+job.name+"…")
+		default:
+			lines = append(lines, "  "+job.verb+" "+job.name)
+		}
+	}
+	lines = append(lines, "", m.progress.ViewAs(float64(m.doneJobs)/float64(len(m.jobs))))
+	return strings.Join(lines, "\n")
+}
+
+func (m dashboard) viewSummary() string {
+	lines := []string{selectorCursorStyle.Bold(true).Render("Summary"), ""}
+	counts := make(map[string]int)
+	for _, job := range m.jobs {
+		counts[job.verb]++
+	}
+	for _, verb := range []string{"Installing", "Reinstalling", "Updating", "Uninstalling"} {
+		if counts[verb] > 0 {
+			lines = append(lines, fmt.Sprintf("%v: %v font(s)", verb, counts[verb]))
+		}
+	}
+	if len(m.errs) == 0 {
+		lines = append(lines, "", installMarkStyle.Render(fmt.Sprintf("All %v change(s) were applied", len(m.jobs))))
+	} else {
+		lines = append(lines, "", uninstallMarkStyle.Render(fmt.Sprintf("%v of %v change(s) failed:", len(m.errs), len(m.jobs))))
+		for _, err := range m.errs {
+			lines = append(lines, "  "+err.Error())
+		}
+	}
+	lines = append(lines, "", glyphHelpStyle.Render(fmt.Sprintf("%v / %v quit", firstKey(keys.Submit), firstKey(keys.Quit))))
+	return strings.Join(lines, "\n")
+}
+
+func (m dashboard) View() string {
+	switch m.state {
+	case dashboardApplying:
+		return m.viewTabs() + "\n\n" + m.viewProgress()
+	case dashboardDone:
+		return m.viewTabs() + "\n\n" + m.viewSummary()
+	}
+
+	help := fmt.Sprintf("1-4 / [ ] tabs • %v mark • %v filter • %v quit", firstKey(keys.Toggle), firstKey(keys.Filter), firstKey(keys.Quit))
+	if count := m.markedCount(); count > 0 {
+		help = fmt.Sprintf("1-4 / [ ] tabs • %v mark • %v filter • %v apply %v change(s) • %v quit",
+			firstKey(keys.Toggle), firstKey(keys.Filter), firstKey(keys.Submit), count, firstKey(keys.Quit))
+	}
+	// the body is fitted by hand as styling it could break the escape sequences of images
+	body := strings.Split(m.viewTab(), "\n")
+	bodyHeight := max(m.height-2, 1)
+	body = body[:min(len(body), bodyHeight)]
+	for len(body) < bodyHeight {
+		body = append(body, "")
+	}
+	return strings.Join([]string{m.viewTabs(), strings.Join(body, "\n"), glyphHelpStyle.Render(help)}, "\n")
+}
+
+func newProgress() progress.Model {
+	if !theme.Current().HasColors() {
+		return progress.New(progress.WithColorProfile(termenv.Ascii))
+	}
+	return progress.New(progress.WithDefaultGradient())
+}
+
+// runDashboard opens the dashboard at tab and applies the changes marked in it
+func runDashboard(database *sql.DB, data types.NerdFonts, source *types.Source, opts types.Options, tab dashboardTab) error {
+	model, err := tea.NewProgram(newDashboard(database, data, source, opts, tab), tea.WithAltScreen()).Run()
+	if err != nil {
+		return err
+	}
+	dashboard := model.(dashboard)
+	if clear := preview.ClearImages(dashboard.browse.protocol); clear != "" {
+		fmt.Print(clear)
+	}
+
+	if opts.DryRun && !dashboard.dryRun.IsEmpty() {
+		handlers.PrintPlan(dashboard.dryRun, opts)
+	}
+	return errors.Join(dashboard.errs...)
+}
+
+// Dashboard opens the tabs for browsing, installing, uninstalling and updating fonts and
+// for the glyphs
+func Dashboard(database *sql.DB, data types.NerdFonts, source *types.Source, opts types.Options) error {
+	return runDashboard(database, data, source, opts, tabBrowse)
+}
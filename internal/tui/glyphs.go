@@ -0,0 +1,354 @@
+package tui
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/getnf/winferior/internal/handlers"
+	"github.com/getnf/winferior/internal/preview"
+	"github.com/getnf/winferior/internal/theme"
+	"github.com/getnf/winferior/internal/types"
+	"golang.org/x/image/font"
+)
+
+const (
+	glyphCellWidth    = 4
+	glyphPreviewWidth = 34
+	glyphLargeRows    = 7
+)
+
+var (
+	glyphTitleStyle    lipgloss.Style
+	glyphClassStyle    lipgloss.Style
+	glyphActiveStyle   lipgloss.Style
+	glyphCellStyle     lipgloss.Style
+	glyphSelectedStyle lipgloss.Style
+	glyphPreviewStyle  lipgloss.Style
+	glyphLargeStyle    lipgloss.Style
+	glyphHelpStyle     lipgloss.Style
+)
+
+func setGlyphStyles(t theme.Theme) {
+	glyphTitleStyle = t.AccentStyle().Bold(true)
+	glyphClassStyle = lipgloss.NewStyle().Padding(0, 1).Foreground(t.Border)
+	glyphActiveStyle = glyphClassStyle.Reverse(true).Foreground(t.Accent)
+	glyphCellStyle = lipgloss.NewStyle().Width(glyphCellWidth).Align(lipgloss.Center)
+	glyphSelectedStyle = glyphCellStyle.Reverse(true).Foreground(t.Selected)
+	glyphPreviewStyle = lipgloss.NewStyle().Width(glyphPreviewWidth).Border(lipgloss.RoundedBorder()).BorderForeground(t.Border).Padding(0, 1)
+	glyphLargeStyle = t.SelectedStyle().Width(glyphPreviewWidth-4).Height(glyphLargeRows).Align(lipgloss.Center, lipgloss.Center).Bold(true)
+	glyphHelpStyle = t.MutedStyle()
+}
+
+type glyphFontMsg struct {
+	face font.Face
+	err  error
+}
+
+// glyphBrowser shows the glyphs of one icon set, or of all of them, as a grid which is
+// filtered by fuzzy matching the names. The selected glyph is drawn large with a font
+// holding the glyphs, see handlers.GlyphPreviewFont.
+type glyphBrowser struct {
+	database *sql.DB
+	data     types.NerdFonts
+	source   *types.Source
+	opts     types.Options
+	glyphs   []types.Glyph
+	classes  []string
+	class    int
+	filter   textinput.Model
+	filtered []types.Glyph
+	cursor   int
+	offset   int
+	width    int
+	height   int
+	status   string
+
+	face        font.Face
+	faceErr     error
+	loadingFace bool
+	large       string
+	largeName   string
+}
+
+func newGlyphBrowser(glyphs []types.Glyph, query string, database *sql.DB, data types.NerdFonts, source *types.Source, opts types.Options) glyphBrowser {
+	filter := textinput.New()
+	filter.Prompt = "/ "
+	filter.Placeholder = "filter by name"
+	filter.SetValue(query)
+
+	m := glyphBrowser{
+		database:    database,
+		data:        data,
+		source:      source,
+		opts:        opts,
+		glyphs:      glyphs,
+		classes:     append([]string{"all"}, types.GlyphClasses(glyphs)...),
+		filter:      filter,
+		width:       80,
+		height:      24,
+		loadingFace: true,
+	}
+	m.applyFilter()
+	return m
+}
+
+// loadFace reads the font the large previews are drawn with, download fetches the
+// symbols font when no font holding the glyphs is installed or cached
+func (m glyphBrowser) loadFace(download bool) tea.Cmd {
+	return func() tea.Msg {
+		fontData, err := handlers.GlyphPreviewFont(m.database, m.data, m.source, m.opts, download)
+		if err != nil {
+			return glyphFontMsg{err: err}
+		}
+		face, err := preview.NewBlocksFace(fontData)
+		return glyphFontMsg{face: face, err: err}
+	}
+}
+
+func (m *glyphBrowser) applyFilter() {
+	var glyphs []types.Glyph
+	for _, glyph := range m.glyphs {
+		if m.class == 0 || glyph.Class() == m.classes[m.class] {
+			glyphs = append(glyphs, glyph)
+		}
+	}
+	if query := strings.TrimSpace(m.filter.Value()); query != "" {
+		glyphs = handlers.SearchGlyphs(glyphs, query, 0)
+	}
+	m.filtered = glyphs
+	m.cursor = 0
+	m.offset = 0
+}
+
+func (m glyphBrowser) columns() int {
+	return max((m.width-glyphPreviewWidth-2)/glyphCellWidth, 1)
+}
+
+func (m glyphBrowser) rows() int {
+	return max(m.height-6, 1)
+}
+
+func (m glyphBrowser) selected() (types.Glyph, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.filtered) {
+		return types.Glyph{}, false
+	}
+	return m.filtered[m.cursor], true
+}
+
+// copyToClipboard sets the clipboard of the terminal through an OSC 52 escape sequence,
+// which also works over ssh
+func copyToClipboard(text string) {
+	sequence := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		sequence = sequence.Tmux()
+	}
+	sequence.WriteTo(os.Stderr)
+}
+
+func (m glyphBrowser) Init() tea.Cmd {
+	return m.loadFace(false)
+}
+
+func (m glyphBrowser) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+	m.syncLarge()
+	return m, cmd
+}
+
+// syncLarge draws the selected glyph when the selection changed, drawing it on every
+// View would rasterize it on every key press
+func (m *glyphBrowser) syncLarge() {
+	glyph, ok := m.selected()
+	if !ok || m.face == nil {
+		m.large = ""
+		m.largeName = ""
+		return
+	}
+	if glyph.Name != m.largeName {
+		m.large = preview.Blocks(m.face, glyph.Char, glyphPreviewWidth-4, glyphLargeRows)
+		m.largeName = glyph.Name
+	}
+}
+
+func (m glyphBrowser) update(msg tea.Msg) (glyphBrowser, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case glyphFontMsg:
+		m.loadingFace = false
+		m.face = msg.face
+		m.faceErr = msg.err
+	case tea.KeyMsg:
+		if m.filter.Focused() {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc", "enter":
+				m.filter.Blur()
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.filter, cmd = m.filter.Update(msg)
+			m.applyFilter()
+			return m, cmd
+		}
+
+		columns := m.columns()
+		switch {
+		case msg.String() == "ctrl+c" || key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+		case msg.String() == "esc":
+			if m.filter.Value() == "" {
+				return m, tea.Quit
+			}
+			m.filter.SetValue("")
+			m.applyFilter()
+		case msg.String() == "tab":
+			m.class = (m.class + 1) % len(m.classes)
+			m.applyFilter()
+		case msg.String() == "shift+tab":
+			m.class = (m.class + len(m.classes) - 1) % len(m.classes)
+			m.applyFilter()
+		case key.Matches(msg, keys.Filter):
+			m.status = ""
+			return m, m.filter.Focus()
+		case key.Matches(msg, keys.Left):
+			m.cursor--
+		case key.Matches(msg, keys.Right):
+			m.cursor++
+		case key.Matches(msg, keys.Up):
+			m.cursor -= columns
+		case key.Matches(msg, keys.Down):
+			m.cursor += columns
+		case key.Matches(msg, keys.PageUp):
+			m.cursor -= columns * m.rows()
+		case key.Matches(msg, keys.PageDown):
+			m.cursor += columns * m.rows()
+		case key.Matches(msg, keys.Top):
+			m.cursor = 0
+		case key.Matches(msg, keys.Bottom):
+			m.cursor = len(m.filtered) - 1
+		case msg.String() == "c" || key.Matches(msg, keys.Submit):
+			if glyph, ok := m.selected(); ok {
+				copyToClipboard(glyph.Char)
+				m.status = fmt.Sprintf("Copied %v %v to the clipboard", glyph.Char, glyph.Name)
+			}
+		case msg.String() == "u":
+			if glyph, ok := m.selected(); ok {
+				codepoint := fmt.Sprintf("U+%04X", glyph.Codepoint())
+				copyToClipboard(codepoint)
+				m.status = fmt.Sprintf("Copied %v to the clipboard", codepoint)
+			}
+		case msg.String() == "p":
+			if m.face == nil && !m.loadingFace {
+				m.loadingFace = true
+				m.faceErr = nil
+				return m, m.loadFace(true)
+			}
+		}
+
+		m.cursor = min(max(m.cursor, 0), max(len(m.filtered)-1, 0))
+		row := m.cursor / columns
+		if row < m.offset {
+			m.offset = row
+		} else if row >= m.offset+m.rows() {
+			m.offset = row - m.rows() + 1
+		}
+	}
+
+	return m, nil
+}
+
+func (m glyphBrowser) viewClasses() string {
+	var classes []string
+	for i, class := range m.classes {
+		if i == m.class {
+			classes = append(classes, glyphActiveStyle.Render(class))
+		} else {
+			classes = append(classes, glyphClassStyle.Render(class))
+		}
+	}
+	return lipgloss.NewStyle().MaxWidth(m.width).Render(lipgloss.JoinHorizontal(lipgloss.Top, classes...))
+}
+
+func (m glyphBrowser) viewGrid() string {
+	if len(m.filtered) == 0 {
+		return "No glyphs match the filter"
+	}
+
+	columns := m.columns()
+	var lines []string
+	for row := m.offset; row < m.offset+m.rows(); row++ {
+		var cells []string
+		for column := 0; column < columns; column++ {
+			i := row*columns + column
+			if i >= len(m.filtered) {
+				break
+			}
+			if i == m.cursor {
+				cells = append(cells, glyphSelectedStyle.Render(m.filtered[i].Char))
+			} else {
+				cells = append(cells, glyphCellStyle.Render(m.filtered[i].Char))
+			}
+		}
+		if len(cells) == 0 {
+			break
+		}
+		lines = append(lines, strings.Join(cells, ""))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m glyphBrowser) viewPreview() string {
+	glyph, ok := m.selected()
+	if !ok {
+		return glyphPreviewStyle.Render("")
+	}
+
+	var large string
+	switch {
+	case m.large != "":
+		large = glyphLargeStyle.Render(m.large)
+	case m.loadingFace:
+		large = glyphLargeStyle.Render(glyph.Char + "\n\n" + glyphHelpStyle.Render("loading the font…"))
+	case errors.Is(m.faceErr, handlers.ErrNotCached):
+		large = glyphLargeStyle.Render(glyph.Char + "\n\n" + glyphHelpStyle.Render("p downloads the symbols font\nto draw the glyph large"))
+	default:
+		large = glyphLargeStyle.Render(glyph.Char + "\n\n" + glyphHelpStyle.Render(fmt.Sprint(m.faceErr)))
+	}
+	details := []string{
+		large,
+		glyphTitleStyle.Render(glyph.Name),
+		fmt.Sprintf("Codepoint   U+%04X", glyph.Codepoint()),
+	}
+	for _, language := range types.GlyphEscapes {
+		details = append(details, fmt.Sprintf("%-11v %v", language, glyph.Escape(language)))
+	}
+	return glyphPreviewStyle.Render(strings.Join(details, "\n"))
+}
+
+func (m glyphBrowser) View() string {
+	header := glyphTitleStyle.Render(fmt.Sprintf("Nerd Fonts glyphs (%v)", len(m.filtered)))
+	body := lipgloss.JoinHorizontal(lipgloss.Top, lipgloss.NewStyle().Width(m.columns()*glyphCellWidth+2).Render(m.viewGrid()), m.viewPreview())
+	help := glyphHelpStyle.Render(fmt.Sprintf("%v/%v/%v/%v move • tab class • %v filter • %v/c copy glyph • u copy codepoint • %v quit",
+		firstKey(keys.Left), firstKey(keys.Down), firstKey(keys.Up), firstKey(keys.Right), firstKey(keys.Filter), firstKey(keys.Submit), firstKey(keys.Quit)))
+	if m.status != "" {
+		help = m.status
+	}
+	return strings.Join([]string{header, m.viewClasses(), m.filter.View(), body, help}, "\n")
+}
+
+// BrowseGlyphs opens the glyph browser, query prefills the filter
+func BrowseGlyphs(glyphs []types.Glyph, query string, database *sql.DB, data types.NerdFonts, source *types.Source, opts types.Options) error {
+	_, err := tea.NewProgram(newGlyphBrowser(glyphs, query, database, data, source, opts), tea.WithAltScreen()).Run()
+	return err
+}
@@ -0,0 +1,490 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/getnf/winferior/internal/handlers"
+	"github.com/getnf/winferior/internal/preview"
+	"github.com/getnf/winferior/internal/theme"
+	"github.com/getnf/winferior/internal/types"
+)
+
+const (
+	selectorListWidth  = 34
+	selectorImageRows  = 8
+	selectorImageWidth = 100
+)
+
+var (
+	selectorCursorStyle    lipgloss.Style
+	selectorSelectedStyle  lipgloss.Style
+	selectorDetailsStyle   lipgloss.Style
+	selectorLabelStyle     lipgloss.Style
+	selectorInstalledStyle lipgloss.Style
+)
+
+func setSelectorStyles(t theme.Theme) {
+	selectorCursorStyle = t.AccentStyle()
+	selectorSelectedStyle = t.SelectedStyle()
+	selectorDetailsStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(t.Border).Padding(0, 1)
+	selectorLabelStyle = t.MutedStyle().Width(11)
+	selectorInstalledStyle = t.MutedStyle()
+}
+
+// installedMarker marks installed fonts with a check and outdated ones with an arrow,
+// selecting them reinstalls or updates them
+func installedMarker(font types.Font) string {
+	switch installVerb(font) {
+	case "Updating":
+		return selectorCursorStyle.Render(" ↑")
+	case "Reinstalling":
+		return selectorInstalledStyle.Render(" ✓")
+	default:
+		return ""
+	}
+}
+
+// installVerb describes what installing font does
+func installVerb(font types.Font) string {
+	switch {
+	case font.InstalledVersion == "-" || font.InstalledVersion == "":
+		return "Installing"
+	case handlers.IsUpdateAvilable(font.AvailableVersion, font.InstalledVersion):
+		return "Updating"
+	default:
+		return "Reinstalling"
+	}
+}
+
+// fontPreview is the rendered sample of a font, image holds the escape sequence showing it
+type fontPreview struct {
+	image string
+	err   error
+}
+
+// fontFiles are the installed files and the cached archive of a font, looking them up
+// walks the install directory so it is done when the highlighted font changes
+type fontFiles struct {
+	name   string
+	usage  types.FontUsage
+	cached bool
+}
+
+type previewMsg struct {
+	name    string
+	preview fontPreview
+}
+
+// installSelector is a multi select of the catalog fonts next to the details and, when
+// the terminal supports graphics, a rendered sample of the highlighted font
+type installSelector struct {
+	data       types.NerdFonts
+	source     *types.Source
+	opts       types.Options
+	fonts      []types.Font
+	aliases    types.FontAliases
+	filtered   []types.Font
+	selected   map[string]bool
+	filter     textinput.Model
+	cursor     int
+	offset     int
+	width      int
+	height     int
+	protocol   preview.Protocol
+	previews   map[string]fontPreview
+	loading    map[string]bool
+	generation int
+	shown      string
+	files      fontFiles
+	confirmed  bool
+	embedded   bool
+}
+
+func newInstallSelector(data types.NerdFonts, fonts []types.Font, aliases types.FontAliases, source *types.Source, opts types.Options) installSelector {
+	filter := textinput.New()
+	filter.Prompt = "/ "
+	filter.Placeholder = "filter fonts"
+
+	m := installSelector{
+		data:     data,
+		source:   source,
+		opts:     opts,
+		fonts:    fonts,
+		aliases:  aliases,
+		filtered: fonts,
+		selected: make(map[string]bool),
+		filter:   filter,
+		width:    80,
+		height:   24,
+		protocol: preview.DetectProtocol(),
+		previews: make(map[string]fontPreview),
+		loading:  make(map[string]bool),
+	}
+	m.syncFiles()
+	return m
+}
+
+// filterFonts fuzzy matches query against the names and aliases of fonts, best matches first
+func filterFonts(fonts []types.Font, query string, aliases types.FontAliases) []types.Font {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return fonts
+	}
+
+	byName := make(map[string]types.Font)
+	var names []string
+	for _, font := range fonts {
+		byName[font.Name] = font
+		names = append(names, font.Name)
+	}
+	var filtered []types.Font
+	for _, name := range handlers.SearchFonts(query, names, aliases) {
+		filtered = append(filtered, byName[name])
+	}
+	return filtered
+}
+
+func (m *installSelector) applyFilter() {
+	m.filtered = filterFonts(m.fonts, m.filter.Value(), m.aliases)
+	m.cursor = 0
+	m.offset = 0
+}
+
+func (m installSelector) listRows() int {
+	rows := m.height - 5
+	if m.protocol != preview.ProtocolNone {
+		rows -= selectorImageRows
+	}
+	return max(rows, 3)
+}
+
+func (m installSelector) imageColumns() int {
+	return max(min(m.width-2, selectorImageWidth), 10)
+}
+
+func (m installSelector) highlighted() (types.Font, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.filtered) {
+		return types.Font{}, false
+	}
+	return m.filtered[m.cursor], true
+}
+
+func (m installSelector) selectedFonts() []types.Font {
+	var fonts []types.Font
+	for _, font := range m.fonts {
+		if m.selected[font.Name] {
+			fonts = append(fonts, m.data.GetFont(font.Name))
+		}
+	}
+	return fonts
+}
+
+// loadPreview renders the sample of name in the background, download allows fetching
+// archives which are not cached
+func (m installSelector) loadPreview(name string, download bool) tea.Cmd {
+	columns := m.imageColumns()
+	return func() tea.Msg {
+		var fontData []byte
+		var err error
+		if download {
+			_, fontData, err = handlers.LoadPreviewFont(m.data, m.source, name, types.VariantDefault, m.opts)
+		} else {
+			_, fontData, err = handlers.CachedPreviewFont(m.data, name, types.VariantDefault, m.opts)
+		}
+		if err != nil {
+			return previewMsg{name: name, preview: fontPreview{err: err}}
+		}
+
+		sample, err := preview.Render(fontData, preview.NewSample(preview.DefaultLines(name), preview.DefaultSize))
+		if err != nil {
+			return previewMsg{name: name, preview: fontPreview{err: err}}
+		}
+		img := preview.Fit(sample, columns*preview.CellWidth, selectorImageRows*preview.CellHeight, preview.DefaultBackground)
+		image, err := preview.TerminalImage(img, m.protocol, columns, selectorImageRows)
+		return previewMsg{name: name, preview: fontPreview{image: image, err: err}}
+	}
+}
+
+// previewHighlighted starts rendering the highlighted font unless that already happened
+func (m *installSelector) previewHighlighted() tea.Cmd {
+	font, ok := m.highlighted()
+	if !ok || m.protocol == preview.ProtocolNone || m.loading[font.Name] {
+		return nil
+	}
+	if _, done := m.previews[font.Name]; done {
+		return nil
+	}
+	m.loading[font.Name] = true
+	return m.loadPreview(font.Name, false)
+}
+
+func (m installSelector) Init() tea.Cmd {
+	return m.previewHighlighted()
+}
+
+func (m installSelector) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+	m.syncImage()
+	m.syncFiles()
+	return m, cmd
+}
+
+// syncFiles looks up the files of the highlighted font when it changed, see viewDetails
+func (m *installSelector) syncFiles() {
+	font, ok := m.highlighted()
+	if !ok {
+		m.files = fontFiles{}
+		return
+	}
+	if font.Name == m.files.name {
+		return
+	}
+	m.files = fontFiles{name: font.Name, cached: handlers.IsCached(font, font.AvailableVersion, m.opts)}
+	if font.InstalledVersion != "-" {
+		m.files.usage = handlers.FontUsage(font, m.opts)
+	}
+}
+
+// syncImage notes when the shown sample changes, see viewImage
+func (m *installSelector) syncImage() {
+	var image string
+	if font, ok := m.highlighted(); ok {
+		image = m.previews[font.Name].image
+	}
+	if image != m.shown {
+		m.shown = image
+		m.generation++
+	}
+}
+
+func (m installSelector) update(msg tea.Msg) (installSelector, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		// the sizes of rendered previews depend on the window
+		m.previews = make(map[string]fontPreview)
+		m.generation++
+		return m, m.previewHighlighted()
+	case previewMsg:
+		delete(m.loading, msg.name)
+		m.previews[msg.name] = msg.preview
+		return m, nil
+	case tea.KeyMsg:
+		if m.filter.Focused() {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc", "enter":
+				m.filter.Blur()
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.filter, cmd = m.filter.Update(msg)
+			m.applyFilter()
+			return m, tea.Batch(cmd, m.previewHighlighted())
+		}
+
+		switch {
+		case msg.String() == "ctrl+c" || key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+		case msg.String() == "esc":
+			if m.filter.Value() == "" {
+				return m, tea.Quit
+			}
+			m.filter.SetValue("")
+			m.applyFilter()
+		case key.Matches(msg, keys.Filter):
+			return m, m.filter.Focus()
+		case key.Matches(msg, keys.Submit):
+			m.confirmed = true
+			return m, tea.Quit
+		case key.Matches(msg, keys.Toggle):
+			if font, ok := m.highlighted(); ok {
+				m.selected[font.Name] = !m.selected[font.Name]
+			}
+		case msg.String() == "p":
+			if font, ok := m.highlighted(); ok && m.protocol != preview.ProtocolNone && !m.loading[font.Name] {
+				m.loading[font.Name] = true
+				delete(m.previews, font.Name)
+				return m, m.loadPreview(font.Name, true)
+			}
+		case key.Matches(msg, keys.Up):
+			m.cursor--
+		case key.Matches(msg, keys.Down):
+			m.cursor++
+		case key.Matches(msg, keys.PageUp):
+			m.cursor -= m.listRows()
+		case key.Matches(msg, keys.PageDown):
+			m.cursor += m.listRows()
+		case key.Matches(msg, keys.Top):
+			m.cursor = 0
+		case key.Matches(msg, keys.Bottom):
+			m.cursor = len(m.filtered) - 1
+		}
+
+		m.cursor = min(max(m.cursor, 0), max(len(m.filtered)-1, 0))
+		if m.cursor < m.offset {
+			m.offset = m.cursor
+		} else if m.cursor >= m.offset+m.listRows() {
+			m.offset = m.cursor - m.listRows() + 1
+		}
+		return m, m.previewHighlighted()
+	}
+
+	return m, nil
+}
+
+func (m installSelector) viewList() string {
+	var lines []string
+	for i := m.offset; i < min(m.offset+m.listRows(), len(m.filtered)); i++ {
+		font := m.filtered[i]
+		cursor := strings.Repeat(" ", lipgloss.Width(theme.Current().Cursor))
+		if i == m.cursor {
+			cursor = selectorCursorStyle.Render(theme.Current().Cursor)
+		}
+		checkbox := theme.Current().UnselectedPrefix
+		name := font.Name
+		if m.selected[font.Name] {
+			checkbox = selectorSelectedStyle.Render(theme.Current().SelectedPrefix)
+			name = selectorSelectedStyle.Render(name)
+		} else if font.InstalledVersion != "-" {
+			name = selectorInstalledStyle.Render(name)
+		}
+		lines = append(lines, cursor+checkbox+name+installedMarker(font))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "  No fonts match the filter")
+	}
+	return lipgloss.NewStyle().Width(selectorListWidth).Height(m.listRows()).MaxWidth(selectorListWidth).Render(strings.Join(lines, "\n"))
+}
+
+func (m installSelector) viewDetails() string {
+	font, ok := m.highlighted()
+	if !ok {
+		return ""
+	}
+
+	detail := func(label string, value string) string {
+		return selectorLabelStyle.Render(label) + value
+	}
+
+	installed := "not installed"
+	variants := "all"
+	if len(m.opts.Variants) > 0 {
+		variants = strings.Join(m.opts.Variants, ", ")
+	}
+	variants += " (to install)"
+	if font.InstalledVersion != "-" {
+		installed = font.InstalledVersion
+		if handlers.IsUpdateAvilable(font.AvailableVersion, font.InstalledVersion) {
+			installed += " (outdated)"
+		}
+		usage := m.files.usage
+		var installedVariants []string
+		for _, variant := range types.Variants {
+			if usage.GetVariantSize(variant) > 0 {
+				installedVariants = append(installedVariants, variant)
+			}
+		}
+		variants = strings.Join(installedVariants, ", ") + fmt.Sprintf(" (%v installed)", humanize.Bytes(uint64(usage.Size)))
+	}
+
+	size := "unknown"
+	if font.Size > 0 {
+		size = humanize.Bytes(uint64(font.Size))
+	}
+	cached := "no"
+	if m.files.cached {
+		cached = "yes"
+	}
+
+	family, license := font.Meta.Family, font.Meta.GetLicense()
+	if family == "" {
+		family = "unknown"
+	}
+	if license == "" {
+		license = "unknown"
+	}
+	design := "proportional"
+	if font.Meta.Monospace {
+		design = "monospace"
+	}
+	if font.Meta.Ligatures {
+		design += ", ligatures"
+	}
+	width := max(m.width-selectorListWidth-2, 20)
+
+	lines := []string{selectorCursorStyle.Bold(true).Render(font.Name)}
+	if font.Meta.Description != "" {
+		lines = append(lines, selectorInstalledStyle.Width(width-4).Render(font.Meta.Description))
+	}
+	lines = append(lines,
+		"",
+		detail("Family", family),
+		detail("Design", design),
+		detail("License", license),
+		detail("Available", fmt.Sprintf("%v (%v)", font.AvailableVersion, m.opts.Channel)),
+		detail("Installed", installed),
+		detail("Variants", variants),
+		detail("Archive", size),
+		detail("Cached", cached),
+		"",
+	)
+
+	switch sample, done := m.previews[font.Name]; {
+	case m.protocol == preview.ProtocolNone:
+		lines = append(lines, "This terminal can not show images,", fmt.Sprintf("run winferior preview %v", font.Name), "to render a sample to a png file.")
+	case m.loading[font.Name]:
+		lines = append(lines, "Rendering a sample…")
+	case done && errors.Is(sample.err, handlers.ErrNotCached):
+		lines = append(lines, "No sample, the font is not cached.", "Press p to download one.")
+	case done && sample.err != nil:
+		lines = append(lines, fmt.Sprintf("No sample: %v", sample.err))
+	}
+
+	return selectorDetailsStyle.Width(width - 2).MaxWidth(width).Render(strings.Join(lines, "\n"))
+}
+
+// viewImage returns the rows reserved for the sample. The sequence is written from the last
+// row after the rows above have been cleared, and the rows change with the image so the
+// renderer clears the remains of the previous one instead of skipping them.
+func (m installSelector) viewImage() string {
+	if m.protocol == preview.ProtocolNone {
+		return ""
+	}
+
+	rows := make([]string, selectorImageRows)
+	for i := range rows {
+		rows[i] = strings.Repeat(" ", m.generation%2)
+	}
+	sequence := preview.ClearImages(m.protocol)
+	if m.shown != "" {
+		sequence = fmt.Sprintf("\x1b[%dA%v\x1b[%dB", selectorImageRows-1, m.shown, selectorImageRows-1)
+	}
+	rows[len(rows)-1] += sequence
+	return strings.Join(rows, "\n")
+}
+
+func (m installSelector) View() string {
+	title := selectorCursorStyle.Bold(true).Render(fmt.Sprintf("Select fonts to install (%v selected)", len(m.selectedFonts())))
+	body := lipgloss.JoinHorizontal(lipgloss.Top, m.viewList(), m.viewDetails())
+	help := glyphHelpStyle.Render(fmt.Sprintf("%v/%v move • %v toggle • %v filter • p download sample • %v install • %v quit",
+		firstKey(keys.Up), firstKey(keys.Down), firstKey(keys.Toggle), firstKey(keys.Filter), firstKey(keys.Submit), firstKey(keys.Quit)))
+
+	sections := []string{title, m.filter.View(), body}
+	if image := m.viewImage(); image != "" {
+		sections = append(sections, image)
+	}
+	if m.embedded {
+		return strings.Join(sections, "\n")
+	}
+	return strings.Join(append(sections, help), "\n")
+}
@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/getnf/winferior/internal/config"
+)
+
+// Keymap holds the keys shared by the lists of the TUI, ctrl+c always quits
+type Keymap struct {
+	Up       key.Binding
+	Down     key.Binding
+	Left     key.Binding
+	Right    key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+	Top      key.Binding
+	Bottom   key.Binding
+	Toggle   key.Binding
+	Filter   key.Binding
+	Submit   key.Binding
+	Quit     key.Binding
+}
+
+var keymapPresets = map[string]config.KeymapConfig{
+	config.KeymapDefault: {
+		Up:       []string{"up", "k", "ctrl+p"},
+		Down:     []string{"down", "j", "ctrl+n"},
+		Left:     []string{"left", "h"},
+		Right:    []string{"right", "l"},
+		PageUp:   []string{"pgup"},
+		PageDown: []string{"pgdown"},
+		Top:      []string{"home", "g"},
+		Bottom:   []string{"end", "G"},
+		Toggle:   []string{"tab", "x"},
+		Filter:   []string{"/", "space"},
+		Submit:   []string{"enter"},
+		Quit:     []string{"q", "ctrl+c"},
+	},
+	config.KeymapVim: {
+		Up:       []string{"k", "up"},
+		Down:     []string{"j", "down"},
+		Left:     []string{"h", "left"},
+		Right:    []string{"l", "right"},
+		PageUp:   []string{"ctrl+b", "ctrl+u", "pgup"},
+		PageDown: []string{"ctrl+f", "ctrl+d", "pgdown"},
+		Top:      []string{"g", "home"},
+		Bottom:   []string{"G", "end"},
+		Toggle:   []string{"x", "space", "tab"},
+		Filter:   []string{"/"},
+		Submit:   []string{"enter"},
+		Quit:     []string{"q", "ctrl+c"},
+	},
+	config.KeymapEmacs: {
+		Up:       []string{"ctrl+p", "up"},
+		Down:     []string{"ctrl+n", "down"},
+		Left:     []string{"ctrl+b", "left"},
+		Right:    []string{"ctrl+f", "right"},
+		PageUp:   []string{"alt+v", "pgup"},
+		PageDown: []string{"ctrl+v", "pgdown"},
+		Top:      []string{"alt+<", "home"},
+		Bottom:   []string{"alt+>", "end"},
+		Toggle:   []string{"ctrl+@", "tab"},
+		Filter:   []string{"ctrl+s", "/"},
+		Submit:   []string{"enter"},
+		Quit:     []string{"ctrl+g", "ctrl+c"},
+	},
+}
+
+// keyNames are the names shown in the help for keys which are hard to read
+var keyNames = map[string]string{
+	"up":     "↑",
+	"down":   "↓",
+	"left":   "←",
+	"right":  "→",
+	"enter":  "⏎",
+	" ":      "space",
+	"ctrl+@": "ctrl+space",
+}
+
+func newBinding(keys []string, help string) key.Binding {
+	var names []string
+	for i, k := range keys {
+		// space is the only key which can not be written in the config
+		if k == "space" {
+			keys[i] = " "
+		}
+		name, ok := keyNames[keys[i]]
+		if !ok {
+			name = keys[i]
+		}
+		names = append(names, name)
+	}
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(strings.Join(names, " / "), help))
+}
+
+// NewKeymap returns the preset keymap of cfg with the keys set in cfg replacing the ones
+// of the preset
+func NewKeymap(cfg config.KeymapConfig) Keymap {
+	preset, ok := keymapPresets[cfg.Preset]
+	if !ok {
+		preset = keymapPresets[config.KeymapDefault]
+	}
+	pick := func(keys []string, presetKeys []string) []string {
+		if len(keys) > 0 {
+			return append([]string{}, keys...)
+		}
+		return append([]string{}, presetKeys...)
+	}
+
+	return Keymap{
+		Up:       newBinding(pick(cfg.Up, preset.Up), "Previous"),
+		Down:     newBinding(pick(cfg.Down, preset.Down), "Next"),
+		Left:     newBinding(pick(cfg.Left, preset.Left), "Left"),
+		Right:    newBinding(pick(cfg.Right, preset.Right), "Right"),
+		PageUp:   newBinding(pick(cfg.PageUp, preset.PageUp), "Previous page"),
+		PageDown: newBinding(pick(cfg.PageDown, preset.PageDown), "Next page"),
+		Top:      newBinding(pick(cfg.Top, preset.Top), "Go to the top"),
+		Bottom:   newBinding(pick(cfg.Bottom, preset.Bottom), "Go to the bottom"),
+		Toggle:   newBinding(pick(cfg.Toggle, preset.Toggle), "Toggle"),
+		Filter:   newBinding(pick(cfg.Filter, preset.Filter), "Filter"),
+		Submit:   newBinding(pick(cfg.Submit, preset.Submit), "Submit"),
+		Quit:     newBinding(pick(cfg.Quit, preset.Quit), "Quit"),
+	}
+}
+
+// firstKey is the name of the first key of binding, used in the one line helps
+func firstKey(binding key.Binding) string {
+	name, _, _ := strings.Cut(binding.Help().Key, " / ")
+	return name
+}
+
+var keys = NewKeymap(config.Default().Keymap)
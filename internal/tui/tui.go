@@ -2,69 +2,119 @@ package tui
 
 import (
 	"database/sql"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/getnf/winferior/internal/db"
-	"github.com/getnf/winferior/internal/handlers"
+	"github.com/getnf/winferior/internal/theme"
 	"github.com/getnf/winferior/internal/types"
 	"github.com/getnf/winferior/internal/utils"
+	"golang.org/x/term"
 )
 
+// Configure sets the theme and the keymap used from now on
+func Configure(t theme.Theme, keymap Keymap) {
+	theme.Set(t)
+	keys = keymap
+	setSelectorStyles(t)
+	setGlyphStyles(t)
+	setDashboardStyles(t)
+}
+
+func init() {
+	Configure(theme.Current(), keys)
+}
+
 func ThemeWinferiorInstall() *huh.Theme {
+	current := theme.Current()
 	t := huh.ThemeBase()
+	if current.Name == theme.PresetCatppuccin {
+		t = huh.ThemeCatppuccin()
+	}
 
-	t.Focused.Base = t.Focused.Base.BorderForeground(lipgloss.Color("7"))
-	t.Focused.Title = t.Focused.Title.Foreground(lipgloss.Color("3"))
-	t.Focused.SelectSelector = t.Focused.SelectSelector.Foreground(lipgloss.Color("3"))
-	t.Focused.MultiSelectSelector = t.Focused.MultiSelectSelector.Foreground(lipgloss.Color("2"))
-	t.Focused.SelectedOption = t.Focused.SelectedOption.Foreground(lipgloss.Color("2"))
-	t.Focused.SelectedPrefix = t.Focused.SelectedPrefix.Foreground(lipgloss.Color("2"))
+	t.Focused.Base = t.Focused.Base.BorderForeground(current.Border)
+	t.Focused.Title = t.Focused.Title.Foreground(current.Accent).Bold(current.Bold)
+	t.Focused.SelectSelector = t.Focused.SelectSelector.Foreground(current.Accent).SetString(current.Cursor)
+	t.Focused.MultiSelectSelector = t.Focused.MultiSelectSelector.Foreground(current.Selected).SetString(current.Cursor)
+	t.Focused.SelectedOption = t.Focused.SelectedOption.Foreground(current.Selected).Bold(current.Bold)
+	t.Focused.SelectedPrefix = t.Focused.SelectedPrefix.Foreground(current.Selected).SetString(current.SelectedPrefix)
+	t.Focused.UnselectedPrefix = t.Focused.UnselectedPrefix.SetString(current.UnselectedPrefix)
+	t.Focused.TextInput.Placeholder = t.Focused.TextInput.Placeholder.Foreground(current.Muted)
+	if !current.HasColors() {
+		t.Focused.FocusedButton = t.Focused.FocusedButton.UnsetForeground().UnsetBackground().Reverse(true)
+		t.Focused.BlurredButton = t.Focused.BlurredButton.UnsetForeground().UnsetBackground()
+	}
+	t.Blurred = t.Focused
+	t.Blurred.Base = t.Blurred.Base.BorderStyle(lipgloss.HiddenBorder())
+	t.Blurred.MultiSelectSelector = lipgloss.NewStyle().SetString(strings.Repeat(" ", lipgloss.Width(current.Cursor)))
 
 	return t
 }
 
 func ThemeWinferiorUninstall() *huh.Theme {
+	current := theme.Current()
 	t := ThemeWinferiorInstall()
 
-	t.Focused.MultiSelectSelector = t.Focused.MultiSelectSelector.Foreground(lipgloss.Color("1"))
-	t.Focused.SelectedOption = t.Focused.SelectedOption.Foreground(lipgloss.Color("1"))
-	t.Focused.SelectedPrefix = t.Focused.SelectedPrefix.Foreground(lipgloss.Color("1"))
+	t.Focused.MultiSelectSelector = t.Focused.MultiSelectSelector.Foreground(current.Danger)
+	t.Focused.SelectedOption = t.Focused.SelectedOption.Foreground(current.Danger)
+	t.Focused.SelectedPrefix = t.Focused.SelectedPrefix.Foreground(current.Danger)
 
 	return t
 }
 
 func myKeyBinds(submitMessage string) *huh.KeyMap {
-	var binding huh.KeyMap
+	binding := huh.NewDefaultKeyMap()
 
-	binding.Quit = key.NewBinding(key.WithKeys("ctrl+c", "q"), key.WithHelp("ctrl+c / q", "quit"))
+	binding.Quit = keys.Quit
 	binding.MultiSelect = huh.MultiSelectKeyMap{
-		Up:          key.NewBinding(key.WithKeys("up", "k", "ctrl+p"), key.WithHelp("k / ↑ / C-p:", "Previous")),
-		Down:        key.NewBinding(key.WithKeys("down", "j", "ctrl+n"), key.WithHelp("j / ↓ / C-n:", "Next")),
-		GotoTop:     key.NewBinding(key.WithKeys("home"), key.WithHelp("Home:", "Go to the top")),
-		GotoBottom:  key.NewBinding(key.WithKeys("end"), key.WithHelp("End:", "Go to the bottom")),
-		Toggle:      key.NewBinding(key.WithKeys("tab"), key.WithHelp("Tab:", "Toggle")),
-		Filter:      key.NewBinding(key.WithKeys(" ", "/"), key.WithHelp("space / /:", "Filter")),
-		SetFilter:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("⏎ :", "Set filter"), key.WithDisabled()),
-		ClearFilter: key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc:", "Clear filter"), key.WithDisabled()),
-		Submit:      key.NewBinding(key.WithKeys("enter"), key.WithHelp("⏎ :", submitMessage)),
+		Up:          keys.Up,
+		Down:        keys.Down,
+		GotoTop:     keys.Top,
+		GotoBottom:  keys.Bottom,
+		Toggle:      keys.Toggle,
+		Filter:      keys.Filter,
+		SetFilter:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("⏎", "Set filter"), key.WithDisabled()),
+		ClearFilter: key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Clear filter"), key.WithDisabled()),
+		Submit:      key.NewBinding(key.WithKeys(keys.Submit.Keys()...), key.WithHelp(keys.Submit.Help().Key, submitMessage)),
 	}
 
-	return &binding
+	return binding
+}
+
+// IsInteractive reports whether winferior runs in a terminal a user can answer prompts in
+func IsInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
 }
 
-func SelectFontsToInstall(data types.NerdFonts, database *sql.DB, downloadPath string, extractPath string, keepTar bool) error {
+// SelectFontsToInstall opens the dashboard at the catalog
+func SelectFontsToInstall(data types.NerdFonts, database *sql.DB, source *types.Source, opts types.Options) error {
+	return runDashboard(database, data, source, opts, tabBrowse)
+}
+
+// SelectFontsToUninstall opens the dashboard at the installed fonts
+func SelectFontsToUninstall(data types.NerdFonts, database *sql.DB, source *types.Source, opts types.Options) error {
+	return runDashboard(database, data, source, opts, tabInstalled)
+}
+
+// SelectFontsToUpdate lets the user pick which of the outdated fonts to update, all of
+// them are selected to begin with
+func SelectFontsToUpdate(outdatedFonts []types.Font) ([]types.Font, error) {
 	var selectedFontsNames []string
-	var selectedFonts []types.Font
-	fontsNames := data.GetFontsNames()
-	optionsFromFonts := huh.NewOptions(fontsNames...)
+	var options []huh.Option[string]
+	for _, font := range outdatedFonts {
+		label := fmt.Sprintf("%-30v %v → %v", font.Name, font.InstalledVersion, font.AvailableVersion)
+		options = append(options, huh.NewOption(label, font.Name).Selected(true))
+	}
 
 	ms := huh.NewMultiSelect[string]().
 		Options(
-			optionsFromFonts...,
+			options...,
 		).
-		Title("Select fonts to install").
+		Title("Select fonts to update").
 		Value(&selectedFontsNames).
 		Filterable(true)
 
@@ -74,58 +124,59 @@ func SelectFontsToInstall(data types.NerdFonts, database *sql.DB, downloadPath s
 		),
 	).WithTheme(
 		ThemeWinferiorInstall(),
-	).WithKeyMap(myKeyBinds("Install fonts"))
-
-	form.Run()
+	).WithKeyMap(myKeyBinds("Update fonts"))
 
-	for _, fontName := range selectedFontsNames {
-		selectedFontName := data.GetFont(fontName)
-		selectedFonts = append(selectedFonts, selectedFontName)
+	err := form.Run()
+	if err != nil {
+		return nil, err
 	}
 
-	for _, font := range selectedFonts {
-		err := handlers.InstallFont(font, downloadPath, extractPath, keepTar)
-		if err != nil {
-			return err
-		}
-		db.InsertIntoInstalledFonts(database, font, data.GetVersion())
+	isSelected := func(f types.Font) bool { return slices.Contains(selectedFontsNames, f.Name) }
+	return utils.Filter(outdatedFonts, isSelected), nil
+}
+
+// CorrectFontName asks which of the suggestions was meant by the unknown font name, an
+// empty name means the font is skipped
+func CorrectFontName(name string, problem string, suggestions []string) (string, error) {
+	var corrected string
+	var options []huh.Option[string]
+	for _, suggestion := range suggestions {
+		options = append(options, huh.NewOption(suggestion, suggestion))
 	}
+	options = append(options, huh.NewOption(fmt.Sprintf("Skip %v", name), ""))
 
-	return nil
-}
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(fmt.Sprintf("%v %v, did you mean", name, problem)).
+				Options(options...).
+				Value(&corrected),
+		),
+	).WithTheme(
+		ThemeWinferiorInstall(),
+	)
 
-func SelectFontsToUninstall(installedFonts []types.Font, database *sql.DB, extractPath string) error {
-	var selectedFonts []string
-	installedFontsNames := utils.Fold(installedFonts, func(f types.Font) string {
-		return f.Name
-	})
-	optionsFromInstalledFonts := huh.NewOptions(installedFontsNames...)
+	err := form.Run()
 
-	ms := huh.NewMultiSelect[string]().
-		Options(
-			optionsFromInstalledFonts...,
-		).
-		Title("Select fonts to uninstall").
-		Value(&selectedFonts).
-		Filterable(true)
+	return corrected, err
+}
+
+func Confirm(title string) (bool, error) {
+	var confirmed bool
 
 	form := huh.NewForm(
 		huh.NewGroup(
-			ms,
+			huh.NewConfirm().
+				Title(title).
+				Affirmative("Yes").
+				Negative("No").
+				Value(&confirmed),
 		),
 	).WithTheme(
 		ThemeWinferiorUninstall(),
-	).WithKeyMap(myKeyBinds("Uninstall fonts"))
-
-	form.Run()
+	)
 
-	for _, font := range selectedFonts {
-		err := handlers.UninstallFont(extractPath, font)
-		if err != nil {
-			return err
-		}
-		db.DeleteInstalledFont(database, font)
-	}
+	err := form.Run()
 
-	return nil
+	return confirmed, err
 }
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"strings"
+	"time"
+)
+
+// Archive cache
+
+// CacheEntry is a font archive kept in the download directory, archives downloaded by
+// older versions of winferior carry no version in their name
+type CacheEntry struct {
+	Name             string
+	Version          string
+	Path             string
+	Size             int64
+	ModTime          time.Time
+	InstalledVersion string
+}
+
+func (e CacheEntry) IsInstalled() bool {
+	return e.Version != "" && e.Version == e.InstalledVersion
+}
+
+func ArchiveName(name string, version string) string {
+	if version == "" {
+		return name + ".tar.xz"
+	}
+	return name + "-" + version + ".tar.xz"
+}
+
+// ParseArchiveName splits an archive name created by ArchiveName into font name and version
+func ParseArchiveName(fileName string) (string, string, bool) {
+	base, found := strings.CutSuffix(fileName, ".tar.xz")
+	if !found {
+		return "", "", false
+	}
+	i := strings.LastIndex(base, "-v")
+	if i <= 0 {
+		return base, "", true
+	}
+	if _, err := ParseVersion(base[i+1:]); err != nil {
+		return base, "", true
+	}
+	return base[:i], base[i+1:], true
+}
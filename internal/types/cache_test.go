@@ -0,0 +1,49 @@
+package types
+
+import "testing"
+
+func TestArchiveName(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		file    string
+	}{
+		{"Hack", "v3.2.1", "Hack-v3.2.1.tar.xz"},
+		{"Hack", "", "Hack.tar.xz"},
+		{"Go-Mono", "v3.2.1", "Go-Mono-v3.2.1.tar.xz"},
+		{"Go-Mono", "", "Go-Mono.tar.xz"},
+		{"iA-Writer", "v3.0.0-rc.2", "iA-Writer-v3.0.0-rc.2.tar.xz"},
+		{"IntelOneMono", "v2.3.0-RC", "IntelOneMono-v2.3.0-RC.tar.xz"},
+	}
+	for _, tt := range tests {
+		file := ArchiveName(tt.name, tt.version)
+		if file != tt.file {
+			t.Errorf("ArchiveName(%q, %q) = %q, want %q", tt.name, tt.version, file, tt.file)
+		}
+		name, version, ok := ParseArchiveName(file)
+		if !ok || name != tt.name || version != tt.version {
+			t.Errorf("ParseArchiveName(%q) = %q, %q, %v, want %q, %q, true", file, name, version, ok, tt.name, tt.version)
+		}
+	}
+}
+
+func TestParseArchiveName(t *testing.T) {
+	tests := []struct {
+		file    string
+		name    string
+		version string
+		ok      bool
+	}{
+		{file: "Hack.zip"},
+		{file: "Hack-v3.2.1.zip"},
+		{file: "notes.txt"},
+		{file: "Hack-vintage.tar.xz", name: "Hack-vintage", ok: true},
+		{file: "-v3.2.1.tar.xz", name: "-v3.2.1", ok: true},
+	}
+	for _, tt := range tests {
+		name, version, ok := ParseArchiveName(tt.file)
+		if name != tt.name || version != tt.version || ok != tt.ok {
+			t.Errorf("ParseArchiveName(%q) = %q, %q, %v, want %q, %q, %v", tt.file, name, version, ok, tt.name, tt.version, tt.ok)
+		}
+	}
+}
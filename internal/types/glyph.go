@@ -0,0 +1,110 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Glyphs
+
+const GlyphNamesUrl = "https://raw.githubusercontent.com/ryanoasis/nerd-fonts/%v/glyphnames.json"
+
+// Glyph is an icon of the Nerd Fonts glyph set as listed in glyphnames.json, Code is the
+// codepoint in hex and Char the glyph itself
+type Glyph struct {
+	Name string `json:"-"`
+	Code string `json:"code"`
+	Char string `json:"char"`
+}
+
+const (
+	EscapeGo         = "go"
+	EscapeShell      = "shell"
+	EscapePowerShell = "powershell"
+	EscapeLua        = "lua"
+)
+
+var GlyphEscapes = []string{EscapeGo, EscapeShell, EscapePowerShell, EscapeLua}
+
+// Class returns the icon set of the glyph, e.g. dev for nf-dev-git
+func (g Glyph) Class() string {
+	class, _, _ := strings.Cut(g.Name, "-")
+	return class
+}
+
+// GlyphClasses returns the sorted icon sets of glyphs
+func GlyphClasses(glyphs []Glyph) []string {
+	var classes []string
+	seen := make(map[string]bool)
+	for _, glyph := range glyphs {
+		if class := glyph.Class(); !seen[class] {
+			seen[class] = true
+			classes = append(classes, class)
+		}
+	}
+	sort.Strings(classes)
+	return classes
+}
+
+func (g Glyph) Codepoint() rune {
+	code, err := strconv.ParseUint(g.Code, 16, 32)
+	if err != nil {
+		return 0
+	}
+	return rune(code)
+}
+
+// Escape returns the escape sequence producing the glyph in a string literal of language
+func (g Glyph) Escape(language string) string {
+	code := g.Codepoint()
+	switch language {
+	case EscapeGo:
+		if code > 0xFFFF {
+			return fmt.Sprintf(`\U%08X`, code)
+		}
+		return fmt.Sprintf(`\u%04X`, code)
+	case EscapeShell:
+		if code > 0xFFFF {
+			return fmt.Sprintf(`$'\U%08X'`, code)
+		}
+		return fmt.Sprintf(`$'\u%04X'`, code)
+	case EscapePowerShell:
+		return fmt.Sprintf("\"`u{%X}\"", code)
+	case EscapeLua:
+		return fmt.Sprintf(`\u{%X}`, code)
+	default:
+		return ""
+	}
+}
+
+// ParseGlyphNames decodes glyphnames.json, which maps the glyph names to their char and
+// code next to a METADATA entry. The glyphs are returned sorted by name.
+func ParseGlyphNames(data []byte) ([]Glyph, error) {
+	var entries map[string]json.RawMessage
+	err := json.Unmarshal(data, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding the glyph names: %v", err)
+	}
+
+	var glyphs []Glyph
+	for name, entry := range entries {
+		if strings.EqualFold(name, "METADATA") {
+			continue
+		}
+		var glyph Glyph
+		if json.Unmarshal(entry, &glyph) != nil || glyph.Code == "" {
+			continue
+		}
+		glyph.Name = name
+		glyphs = append(glyphs, glyph)
+	}
+	if len(glyphs) == 0 {
+		return nil, fmt.Errorf("the glyph names are empty")
+	}
+
+	sort.Slice(glyphs, func(i, j int) bool { return glyphs[i].Name < glyphs[j].Name })
+	return glyphs, nil
+}
@@ -0,0 +1,180 @@
+package types
+
+import (
+	"encoding/json"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Font metadata
+
+const FontsJsonUrl = "https://raw.githubusercontent.com/ryanoasis/nerd-fonts/%v/bin/scripts/lib/fonts.json"
+
+// FontMeta describes the design of a font. Family, License and Description come from the
+// fonts.json of Nerd Fonts, ArchiveLicense is detected from the license files of the
+// archive once it was downloaded. Aliases are the unpatched and patched names of the
+// fonts.json, they are stored as font aliases.
+type FontMeta struct {
+	Name           string
+	Family         string
+	License        string
+	ArchiveLicense string
+	Description    string
+	Ligatures      bool
+	Monospace      bool
+	Aliases        []string
+}
+
+// GetLicense prefers the license found in the archive over the one Nerd Fonts lists
+func (m FontMeta) GetLicense() string {
+	if m.ArchiveLicense != "" {
+		return m.ArchiveLicense
+	}
+	return m.License
+}
+
+// ligatureFonts are the fonts with programming ligatures
+var ligatureFonts = []string{
+	"0xProto",
+	"CascadiaCode",
+	"FiraCode",
+	"Hasklig",
+	"IntelOneMono",
+	"Iosevka",
+	"IosevkaTerm",
+	"IosevkaTermSlab",
+	"JetBrainsMono",
+	"Lilex",
+	"Monaspace",
+	"Monoid",
+	"Mononoki",
+	"Recursive",
+	"VictorMono",
+	"ZedMono",
+}
+
+// proportionalFonts are the fonts without a monospaced family, archives shipping a mono
+// family next to proportional ones, like Noto, count as monospace
+var proportionalFonts = []string{
+	"Arimo",
+	"NerdFontsSymbolsOnly",
+	"Tinos",
+	"Ubuntu",
+	"UbuntuSans",
+}
+
+// ParseFontsJson reads the fonts.json of the Nerd Fonts repository. Its entries are keyed
+// by the folder the patched font is released as, the entries sharing an archive, like
+// the families of Noto, are merged. The fonts.json does not say which fonts have
+// ligatures or are monospaced, those come from the builtin metadata.
+func ParseFontsJson(data []byte) ([]FontMeta, error) {
+	var fontsJson struct {
+		Fonts []struct {
+			UnpatchedName string `json:"unpatchedName"`
+			PatchedName   string `json:"patchedName"`
+			LicenseId     string `json:"licenseId"`
+			FolderName    string `json:"folderName"`
+			Description   string `json:"description"`
+		} `json:"fonts"`
+	}
+	err := json.Unmarshal(data, &fontsJson)
+	if err != nil {
+		return nil, err
+	}
+
+	var metas []FontMeta
+	indexes := make(map[string]int)
+	for _, font := range fontsJson.Fonts {
+		// folders of families sharing an archive are nested in the folder of the archive
+		name, _, _ := strings.Cut(font.FolderName, "/")
+		if name == "" {
+			continue
+		}
+		i, ok := indexes[name]
+		if !ok {
+			i = len(metas)
+			indexes[name] = i
+			metas = append(metas, BuiltinFontMeta([]Font{{Name: name}})[0])
+		}
+		meta := &metas[i]
+		if font.UnpatchedName != "" && !slices.Contains(strings.Split(meta.Family, ", "), font.UnpatchedName) {
+			meta.Family = strings.TrimPrefix(meta.Family+", "+font.UnpatchedName, ", ")
+		}
+		for _, alias := range []string{font.UnpatchedName, font.PatchedName} {
+			if alias != "" && !slices.Contains(meta.Aliases, alias) {
+				meta.Aliases = append(meta.Aliases, alias)
+			}
+		}
+		if meta.License == "" {
+			meta.License = font.LicenseId
+		}
+		if meta.Description == "" {
+			meta.Description = font.Description
+		}
+	}
+	return metas, nil
+}
+
+// IsLicenseFile reports whether the file at name in an archive holds a license
+func IsLicenseFile(name string) bool {
+	name = strings.ToLower(path.Base(strings.ReplaceAll(name, "\\", "/")))
+	for _, prefix := range []string{"license", "licence", "ofl", "copying"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// licenseMarkers identifies licenses by a phrase of their text, the more specific
+// phrases come first
+var licenseMarkers = []struct {
+	marker  string
+	license string
+}{
+	{"sil open font license", "OFL-1.1"},
+	{"ubuntu font licence", "UFL-1.0"},
+	{"bitstream vera", "Bitstream-Vera"},
+	{"ipa font license", "IPA"},
+	{"apache license", "Apache-2.0"},
+	{"mit license", "MIT"},
+	{"permission is hereby granted, free of charge", "MIT"},
+	{"redistribution and use in source and binary forms", "BSD-3-Clause"},
+}
+
+// DetectLicense returns the SPDX identifier of the license text, or an empty string when
+// the license is not known
+func DetectLicense(text string) string {
+	text = strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	for _, m := range licenseMarkers {
+		if strings.Contains(text, m.marker) {
+			return m.license
+		}
+	}
+	return ""
+}
+
+// FontMetaAliases returns the aliases of metas
+func FontMetaAliases(metas []FontMeta) []FontAlias {
+	var aliases []FontAlias
+	for _, meta := range metas {
+		for _, alias := range meta.Aliases {
+			aliases = append(aliases, FontAlias{Alias: alias, Name: meta.Name})
+		}
+	}
+	return aliases
+}
+
+// BuiltinFontMeta returns the metadata known to winferior for the fonts of a catalog
+func BuiltinFontMeta(fonts []Font) []FontMeta {
+	var metas []FontMeta
+	for _, font := range fonts {
+		metas = append(metas, FontMeta{
+			Name:      font.Name,
+			Ligatures: slices.Contains(ligatureFonts, font.Name),
+			Monospace: !slices.Contains(proportionalFonts, font.Name),
+		})
+	}
+	return metas
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"slices"
+	"testing"
+)
+
+// catalogFonts are the font archives of the Nerd Fonts v3 releases
+var catalogFonts = []string{
+	"0xProto", "3270", "Agave", "AnonymousPro", "Arimo", "AurulentSansMono", "BigBlueTerminal",
+	"BitstreamVeraSansMono", "CascadiaCode", "CascadiaMono", "CodeNewRoman", "ComicShannsMono",
+	"CommitMono", "Cousine", "D2Coding", "DaddyTimeMono", "DejaVuSansMono", "DepartureMono",
+	"DroidSansMono", "EnvyCodeR", "FantasqueSansMono", "FiraCode", "FiraMono", "GeistMono",
+	"Go-Mono", "Gohu", "Hack", "Hasklig", "HeavyData", "Hermit", "iA-Writer", "IBMPlexMono",
+	"Inconsolata", "InconsolataGo", "InconsolataLGC", "IntelOneMono", "Iosevka", "IosevkaTerm",
+	"IosevkaTermSlab", "JetBrainsMono", "Lekton", "LiberationMono", "Lilex", "MartianMono",
+	"Meslo", "Monaspace", "Monofur", "Monoid", "Mononoki", "MPlus", "NerdFontsSymbolsOnly",
+	"Noto", "OpenDyslexic", "Overpass", "ProFont", "ProggyClean", "Recursive", "RobotoMono",
+	"ShareTechMono", "SourceCodePro", "SpaceMono", "Terminus", "Tinos", "Ubuntu", "UbuntuMono",
+	"UbuntuSans", "VictorMono", "ZedMono",
+}
+
+func TestBuiltinMetaNames(t *testing.T) {
+	tests := []struct {
+		list  string
+		names []string
+	}{
+		{"ligatureFonts", ligatureFonts},
+		{"proportionalFonts", proportionalFonts},
+	}
+	for _, tt := range tests {
+		for _, name := range tt.names {
+			if !slices.Contains(catalogFonts, name) {
+				t.Errorf("%v has %q, which is not a font of the catalog", tt.list, name)
+			}
+		}
+	}
+	for _, alias := range BuiltinFontAliases() {
+		if !slices.Contains(catalogFonts, alias.Name) {
+			t.Errorf("the alias %q points at %q, which is not a font of the catalog", alias.Alias, alias.Name)
+		}
+	}
+}
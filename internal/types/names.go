@@ -0,0 +1,188 @@
+package types
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/lithammer/fuzzysearch/fuzzy"
+)
+
+// Font names
+
+// nerdFontSuffixes are appended by Nerd Fonts to the family names of patched fonts, e.g.
+// "FiraCode Nerd Font Mono" or "JetBrainsMono NFP"
+var nerdFontSuffixes = []string{"nerdfontmono", "nerdfontpropo", "nerdfont", "nfm", "nfp", "nf"}
+
+// NormalizeFontName lowercases name and drops spaces, dashes, underscores and the suffixes
+// Nerd Fonts adds to family names, "jetbrains mono" and "JetBrainsMono Nerd Font" both
+// become "jetbrainsmono"
+func NormalizeFontName(name string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+		}
+	}
+	normalized := builder.String()
+	for _, suffix := range nerdFontSuffixes {
+		if trimmed, found := strings.CutSuffix(normalized, suffix); found && trimmed != "" {
+			return trimmed
+		}
+	}
+	return normalized
+}
+
+// FontAlias is another name of a font of the catalog, the name of the unpatched font or
+// the name Nerd Fonts renamed the patched font to
+type FontAlias struct {
+	Alias string
+	Name  string
+}
+
+// builtinFontAliases are the renames of Nerd Fonts, the patched fonts of reserved font
+// names carry a different name than the font they are made from
+var builtinFontAliases = []FontAlias{
+	{"IBM 3270", "3270"},
+	{"Anonymice", "AnonymousPro"},
+	{"BigBlueTerm", "BigBlueTerminal"},
+	{"BigBlueTerm437", "BigBlueTerminal"},
+	{"BitstromWera", "BitstreamVeraSansMono"},
+	{"CaskaydiaCove", "CascadiaCode"},
+	{"CaskaydiaMono", "CascadiaMono"},
+	{"FantasqueSansM", "FantasqueSansMono"},
+	{"GohuFont", "Gohu"},
+	{"Hasklug", "Hasklig"},
+	{"Hurmit", "Hermit"},
+	{"BlexMono", "IBMPlexMono"},
+	{"iMWriting", "iA-Writer"},
+	{"iA Writer Mono", "iA-Writer"},
+	{"iA Writer Duo", "iA-Writer"},
+	{"iA Writer Quattro", "iA-Writer"},
+	{"IntoneMono", "IntelOneMono"},
+	{"Literation", "LiberationMono"},
+	{"LiterationMono", "LiberationMono"},
+	{"MesloLG", "Meslo"},
+	{"MesloLGS", "Meslo"},
+	{"MesloLGM", "Meslo"},
+	{"MesloLGL", "Meslo"},
+	{"Monaspice", "Monaspace"},
+	{"Monaspace Argon", "Monaspace"},
+	{"Monaspace Krypton", "Monaspace"},
+	{"Monaspace Neon", "Monaspace"},
+	{"Monaspace Radon", "Monaspace"},
+	{"Monaspace Xenon", "Monaspace"},
+	{"Symbols", "NerdFontsSymbolsOnly"},
+	{"Overpass Mono", "Overpass"},
+	{"ProggyCleanTT", "ProggyClean"},
+	{"RecMono", "Recursive"},
+	{"SauceCodePro", "SourceCodePro"},
+	{"ShureTechMono", "ShareTechMono"},
+	{"Terminess", "Terminus"},
+	{"TerminessTTF", "Terminus"},
+}
+
+// BuiltinFontAliases returns the aliases known to winferior
+func BuiltinFontAliases() []FontAlias {
+	return slices.Clone(builtinFontAliases)
+}
+
+// FontAliases maps normalized aliases to the names of the catalog, see NormalizeFontName
+type FontAliases map[string]string
+
+func NewFontAliases(aliases []FontAlias) FontAliases {
+	a := make(FontAliases)
+	for _, alias := range aliases {
+		if normalized := NormalizeFontName(alias.Alias); normalized != "" && normalized != NormalizeFontName(alias.Name) {
+			a[normalized] = alias.Name
+		}
+	}
+	return a
+}
+
+// Resolve returns the name of the catalog name is an alias of
+func (a FontAliases) Resolve(name string) (string, bool) {
+	target, ok := a[NormalizeFontName(name)]
+	return target, ok
+}
+
+// MatchFontName returns the name among names which name refers to, matching exactly,
+// ignoring the case, by the normalized names or by an alias
+func MatchFontName(name string, names []string, aliases FontAliases) (string, bool) {
+	for _, candidate := range names {
+		if candidate == name {
+			return candidate, true
+		}
+	}
+	for _, candidate := range names {
+		if strings.EqualFold(candidate, name) {
+			return candidate, true
+		}
+	}
+	normalized := NormalizeFontName(name)
+	for _, candidate := range names {
+		if NormalizeFontName(candidate) == normalized {
+			return candidate, true
+		}
+	}
+	if target, ok := aliases.Resolve(name); ok && slices.Contains(names, target) {
+		return target, true
+	}
+	return "", false
+}
+
+// SuggestFontNames returns up to limit names among names which name might be a typo of.
+// Names containing the letters of name in order come first, followed by the names within
+// a few edits of it, the aliases of names are compared as well.
+func SuggestFontNames(name string, names []string, aliases FontAliases, limit int) []string {
+	normalized := NormalizeFontName(name)
+	if normalized == "" {
+		return nil
+	}
+
+	type suggestion struct {
+		name     string
+		fuzzy    bool
+		distance int
+	}
+	var suggestions []suggestion
+	suggest := func(candidate string, target string) {
+		distance := fuzzy.LevenshteinDistance(normalized, target)
+		switch {
+		case fuzzy.Match(normalized, target) || fuzzy.Match(target, normalized):
+			suggestions = append(suggestions, suggestion{candidate, true, distance})
+		case distance <= max(2, len(normalized)/3):
+			suggestions = append(suggestions, suggestion{candidate, false, distance})
+		}
+	}
+	for _, candidate := range names {
+		suggest(candidate, NormalizeFontName(candidate))
+	}
+	for alias, candidate := range aliases {
+		if slices.Contains(names, candidate) {
+			suggest(candidate, alias)
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].fuzzy != suggestions[j].fuzzy {
+			return suggestions[i].fuzzy
+		}
+		if suggestions[i].distance != suggestions[j].distance {
+			return suggestions[i].distance < suggestions[j].distance
+		}
+		return suggestions[i].name < suggestions[j].name
+	})
+
+	var results []string
+	for _, s := range suggestions {
+		if len(results) == limit {
+			break
+		}
+		// a font suggested by its name and its aliases is listed once
+		if !slices.Contains(results, s.name) {
+			results = append(results, s.name)
+		}
+	}
+	return results
+}
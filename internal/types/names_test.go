@@ -0,0 +1,81 @@
+package types
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeFontName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"JetBrainsMono", "jetbrainsmono"},
+		{"jetbrains mono", "jetbrainsmono"},
+		{"JetBrains-Mono", "jetbrainsmono"},
+		{"jetbrains_mono", "jetbrainsmono"},
+		{"JetBrainsMono Nerd Font", "jetbrainsmono"},
+		{"JetBrainsMono Nerd Font Mono", "jetbrainsmono"},
+		{"JetBrainsMono NFP", "jetbrainsmono"},
+		{"Go-Mono", "gomono"},
+		{"3270", "3270"},
+		{"Nerd Font", "nerdfont"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeFontName(tt.name); got != tt.want {
+			t.Errorf("NormalizeFontName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatchFontName(t *testing.T) {
+	aliases := NewFontAliases(BuiltinFontAliases())
+	tests := []struct {
+		name   string
+		want   string
+		wantOk bool
+	}{
+		{"FiraCode", "FiraCode", true},
+		{"firacode", "FiraCode", true},
+		{"FIRACODE", "FiraCode", true},
+		{"Fira Code", "FiraCode", true},
+		{"fira-code", "FiraCode", true},
+		{"FiraCode Nerd Font", "FiraCode", true},
+		{"go mono", "Go-Mono", true},
+		{"ia writer", "iA-Writer", true},
+		{"SauceCodePro", "SourceCodePro", true},
+		{"Sauce Code Pro Nerd Font", "SourceCodePro", true},
+		{"caskaydia cove", "CascadiaCode", true},
+		{"Terminess", "Terminus", true},
+		{"Firacod", "", false},
+		{"NoSuchFont", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := MatchFontName(tt.name, catalogFonts, aliases)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("MatchFontName(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func TestSuggestFontNames(t *testing.T) {
+	aliases := NewFontAliases(BuiltinFontAliases())
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"Firacod", []string{"FiraCode"}},
+		{"JetBrianMono", []string{"JetBrainsMono"}},
+		{"SaucCodePro", []string{"SourceCodePro"}},
+		{"caskaydia", []string{"CascadiaCode", "CascadiaMono"}},
+		{"xyzzy", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := SuggestFontNames(tt.name, catalogFonts, aliases, 3); !slices.Equal(got, tt.want) {
+			t.Errorf("SuggestFontNames(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package types
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/adrg/xdg"
+)
+
+// Scope decides whether fonts are installed for every user of the machine, which needs
+// admin rights, or only for the current user
+type Scope string
+
+const (
+	ScopeMachine Scope = "machine"
+	ScopeUser    Scope = "user"
+)
+
+func (s Scope) IsValid() bool {
+	return s == ScopeMachine || s == ScopeUser
+}
+
+// RegistryHive returns the short name of the registry hive fonts of scope are registered in
+func (s Scope) RegistryHive() string {
+	if s == ScopeUser {
+		return "HKCU"
+	}
+	return "HKLM"
+}
+
+func (s Scope) DefaultInstallPath() string {
+	if s == ScopeUser {
+		return xdg.FontDirs[len(xdg.FontDirs)-1]
+	}
+	return xdg.FontDirs[0]
+}
+
+// Font variants as shipped in the Nerd Fonts archives, e.g. HackNerdFont-Regular.ttf,
+// HackNerdFontMono-Regular.ttf and HackNerdFontPropo-Regular.ttf
+const (
+	VariantDefault = "default"
+	VariantMono    = "mono"
+	VariantPropo   = "propo"
+)
+
+var Variants = []string{VariantDefault, VariantMono, VariantPropo}
+
+// FontVariant returns the variant of a font file, or an empty string for files which
+// are not fonts like licenses and readmes
+func FontVariant(fileName string) string {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if ext != ".ttf" && ext != ".otf" {
+		return ""
+	}
+	name := strings.Split(filepath.Base(fileName), "-")[0]
+	switch {
+	case strings.HasSuffix(name, "NerdFontMono"):
+		return VariantMono
+	case strings.HasSuffix(name, "NerdFontPropo"):
+		return VariantPropo
+	default:
+		return VariantDefault
+	}
+}
+
+// Options are the settings install, update and uninstall operations run with
+type Options struct {
+	DownloadPath string
+	ExtractPath  string
+	KeepTars     bool
+	Scope        Scope
+	Channel      Channel
+	Variants     []string
+	Parallelism  int
+	DryRun       bool
+}
+
+func (o Options) WantsVariant(variant string) bool {
+	if len(o.Variants) == 0 {
+		return true
+	}
+	for _, v := range o.Variants {
+		if v == variant {
+			return true
+		}
+	}
+	return false
+}
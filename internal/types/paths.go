@@ -25,16 +25,23 @@ func (p *Paths) GetDbPath() string {
 	return p.Db
 }
 
-func NewPaths() *Paths {
-	paths := &Paths{}
-
-	paths.Download = filepath.Join(xdg.UserDirs.Download, "WiNFerior")
-	paths.Install = xdg.FontDirs[0]
-	paths.Db = filepath.Join(xdg.DataHome, "WiNFerior")
+// NewPaths creates the given directories, empty ones fall back to the defaults of scope
+func NewPaths(download string, install string, db string, scope Scope) *Paths {
+	paths := &Paths{Download: download, Install: install, Db: db}
+
+	if paths.Download == "" {
+		paths.Download = filepath.Join(xdg.UserDirs.Download, "WiNFerior")
+	}
+	if paths.Install == "" {
+		paths.Install = scope.DefaultInstallPath()
+	}
+	if paths.Db == "" {
+		paths.Db = filepath.Join(xdg.DataHome, "WiNFerior")
+	}
 
 	os.MkdirAll(paths.Download, 0755)
 	os.MkdirAll(paths.Install, 0755)
 	os.MkdirAll(paths.Db, 0755)
 
 	return paths
-}
\ No newline at end of file
+}
@@ -0,0 +1,86 @@
+package types
+
+// Plans
+
+type ActionKind string
+
+const (
+	ActionDownload            ActionKind = "download"
+	ActionUseCache            ActionKind = "use cached archive"
+	ActionExtract             ActionKind = "extract"
+	ActionDeleteArchive       ActionKind = "delete archive"
+	ActionRemoveFile          ActionKind = "remove file"
+	ActionRemoveDir           ActionKind = "remove dir"
+	ActionRemoveRegistryValue ActionKind = "remove registry value"
+	ActionDbInsert            ActionKind = "db insert"
+	ActionDbUpdate            ActionKind = "db update"
+	ActionDbDelete            ActionKind = "db delete"
+	ActionDeleteDb            ActionKind = "delete database"
+)
+
+// Action is a single side effect of a plan. Path is the url, archive, directory or file
+// the action works on, for registry actions it is the font file the value belongs to.
+// Target is where downloads and extractions end up.
+type Action struct {
+	Kind    ActionKind
+	Font    Font
+	Path    string
+	Target  string
+	Size    int64
+	Version string
+	Channel Channel
+}
+
+// Plan is the list of side effects of an install, update or uninstall, it is either
+// printed for a dry run or applied in order
+type Plan struct {
+	Actions []Action
+}
+
+func (p *Plan) Add(action Action) {
+	p.Actions = append(p.Actions, action)
+}
+
+func (p Plan) IsEmpty() bool {
+	return len(p.Actions) == 0
+}
+
+func (p Plan) GetActions(kind ActionKind) []Action {
+	var actions []Action
+	for _, action := range p.Actions {
+		if action.Kind == kind {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// GetFontsNames returns the fonts the plan touches in the order they first appear
+func (p Plan) GetFontsNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, action := range p.Actions {
+		if !seen[action.Font.Name] {
+			seen[action.Font.Name] = true
+			names = append(names, action.Font.Name)
+		}
+	}
+	return names
+}
+
+// Append adds the actions of other after the actions of p
+func (p *Plan) Append(other Plan) {
+	p.Actions = append(p.Actions, other.Actions...)
+}
+
+func (p Plan) GetSize(kind ActionKind) int64 {
+	var size int64
+	for _, action := range p.GetActions(kind) {
+		size += action.Size
+	}
+	return size
+}
+
+func (p Plan) GetDownloadSize() int64 {
+	return p.GetSize(ActionDownload)
+}
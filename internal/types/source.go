@@ -0,0 +1,89 @@
+package types
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const DefaultApiUrl = "https://api.github.com/repos/ryanoasis/nerd-fonts"
+
+const githubApiHost = "api.github.com"
+
+// Source describes where the release catalog and the font archives are fetched from.
+// ApiUrl points at a GitHub compatible repository endpoint, e.g. an Artifactory remote
+// repository mirroring api.github.com. Asset urls starting with RewriteFrom get that
+// prefix replaced by RewriteTo before being downloaded. Token is sent as a bearer token
+// to api.github.com to get the higher rate limit of authenticated requests, see GetToken.
+type Source struct {
+	ApiUrl      string
+	RewriteFrom string
+	RewriteTo   string
+	Token       string
+}
+
+func (s *Source) GetApiUrl() string {
+	return strings.TrimSuffix(s.ApiUrl, "/")
+}
+
+// GetToken returns the token to authenticate to the API with. It is a GitHub token, often
+// taken from GITHUB_TOKEN, so it is only handed to api.github.com and never to a mirror.
+func (s *Source) GetToken() string {
+	apiUrl, err := url.Parse(s.GetApiUrl())
+	if err != nil || apiUrl.Scheme != "https" || !strings.EqualFold(apiUrl.Hostname(), githubApiHost) {
+		return ""
+	}
+	return s.Token
+}
+
+func (s *Source) GetLatestReleaseUrl() string {
+	return s.GetApiUrl() + "/releases/latest"
+}
+
+func (s *Source) GetReleasesUrl() string {
+	return s.GetApiUrl() + "/releases?per_page=30"
+}
+
+func (s *Source) GetReleasesPageUrl(page int) string {
+	return fmt.Sprintf("%v/releases?per_page=100&page=%d", s.GetApiUrl(), page)
+}
+
+func (s *Source) RewriteAssetUrl(url string) string {
+	if s.RewriteFrom == "" || !strings.HasPrefix(url, s.RewriteFrom) {
+		return url
+	}
+	return s.RewriteTo + strings.TrimPrefix(url, s.RewriteFrom)
+}
+
+// SetAssetRewrite parses a rewrite rule in the form "from=to"
+func (s *Source) SetAssetRewrite(rule string) {
+	from, to, found := strings.Cut(rule, "=")
+	if !found {
+		return
+	}
+	s.RewriteFrom = strings.TrimSpace(from)
+	s.RewriteTo = strings.TrimSpace(to)
+}
+
+// NewSource creates a source, an empty apiUrl falls back to the Nerd Fonts repository
+func NewSource(apiUrl string, assetRewrite string, token string) *Source {
+	source := &Source{ApiUrl: apiUrl, Token: token}
+
+	if source.ApiUrl == "" {
+		source.ApiUrl = DefaultApiUrl
+	}
+	source.SetAssetRewrite(assetRewrite)
+
+	return source
+}
+
+// GetGlyphNamesUrl returns the url of the glyph names of the release version, the asset
+// rewrite applies to it as well
+func (s *Source) GetGlyphNamesUrl(version string) string {
+	return s.RewriteAssetUrl(fmt.Sprintf(GlyphNamesUrl, version))
+}
+
+// GetFontsJsonUrl returns the url of the font metadata of the release version
+func (s *Source) GetFontsJsonUrl(version string) string {
+	return s.RewriteAssetUrl(fmt.Sprintf(FontsJsonUrl, version))
+}
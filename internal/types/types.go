@@ -10,8 +10,13 @@ import (
 // Fonts
 
 type NerdFonts struct {
-	Version string `json:"tag_name"`
-	Fonts   []Font `json:"assets"`
+	Version     string `json:"tag_name"`
+	Name        string `json:"name"`
+	Body        string `json:"body"`
+	PublishedAt string `json:"published_at"`
+	Prerelease  bool   `json:"prerelease"`
+	Draft       bool   `json:"draft"`
+	Fonts       []Font `json:"assets"`
 }
 
 type Font struct {
@@ -19,8 +24,14 @@ type Font struct {
 	Name               string `json:"name"`
 	ContentType        string `json:"content_type"`
 	BrowserDownloadUrl string `json:"browser_download_url"`
+	Size               int64  `json:"size"`
+	Digest             string `json:"digest"`
 	AvailableVersion   string
 	InstalledVersion   string
+	InstalledSize      int64
+	InstalledAt        string
+	Channel            Channel
+	Meta               FontMeta
 }
 
 func (fs NerdFonts) GetVersion() string {
@@ -40,6 +51,15 @@ func (fs NerdFonts) GetFont(f string) Font {
 	return font[0]
 }
 
+func (fs NerdFonts) HasFont(f string) bool {
+	for _, font := range fs.Fonts {
+		if font.Name == f {
+			return true
+		}
+	}
+	return false
+}
+
 func (fs NerdFonts) GetFontsNames() []string {
 	fontNames := utils.Fold(fs.Fonts, func(f Font) string {
 		return f.Name
@@ -56,6 +76,37 @@ func (f *Font) AddAvailableVersion(ver string) {
 	f.AvailableVersion = ver
 }
 
+// Release channels
+
+// Channel selects which releases fonts follow, stable only sees the latest release while
+// prerelease follows the newest release including release candidates
+type Channel string
+
+const (
+	ChannelStable     Channel = "stable"
+	ChannelPrerelease Channel = "prerelease"
+)
+
+func (c Channel) IsValid() bool {
+	return c == ChannelStable || c == ChannelPrerelease
+}
+
+// LatestRelease picks the release channel follows out of a list of releases
+func (c Channel) LatestRelease(releases []NerdFonts) (NerdFonts, bool) {
+	var latest NerdFonts
+	found := false
+	for _, release := range releases {
+		if release.Draft || (release.Prerelease && c != ChannelPrerelease) {
+			continue
+		}
+		if !found || CompareVersions(release.GetVersion(), latest.GetVersion()) > 0 {
+			latest = release
+			found = true
+		}
+	}
+	return latest, found
+}
+
 // Command line argumetns
 
 type InstallCmd struct {
@@ -64,25 +115,151 @@ type InstallCmd struct {
 
 type UninstallCmd struct {
 	Fonts []string `arg:"positional" help:"list of space separated fonts to uninstall"`
+	All   bool     `arg:"-a" help:"uninstall all installed fonts"`
+	Yes   bool     `arg:"-y" help:"do not ask for confirmation"`
+}
+
+type PurgeCmd struct {
+	Db  bool `arg:"--db" help:"also delete the database"`
+	Yes bool `arg:"-y" help:"do not ask for confirmation"`
 }
 
 type ListCmd struct {
-	Installed bool `arg:"-i" help:"list only installed fonts"`
+	Query        string `arg:"positional" help:"fuzzy search the names of fonts"`
+	Installed    bool   `arg:"-i" help:"list only installed fonts"`
+	NotInstalled bool   `arg:"--not-installed" help:"list only fonts which are not installed"`
+	Outdated     bool   `arg:"-o,--outdated" help:"list only installed fonts with updates"`
+	Ligatures    bool   `arg:"-l,--ligatures" help:"list only fonts with programming ligatures"`
+	Monospace    bool   `arg:"-m,--monospace" help:"list only monospaced fonts"`
+	Sort         string `arg:"--sort" help:"sort by name, size or installed-date, search results are sorted by relevance by default"`
+	Size         bool   `arg:"-s,--size" help:"show the archive and installed size of fonts"`
+}
+
+const (
+	SortName          = "name"
+	SortSize          = "size"
+	SortInstalledDate = "installed-date"
+)
+
+type DuCmd struct {
+	Fonts   []string `arg:"positional" help:"list of space separated installed fonts, all installed fonts by default"`
+	Keep    []string `arg:"--keep" help:"variants to keep when reclaiming space: default, mono and propo, the variants setting by default"`
+	Reclaim bool     `arg:"-r" help:"uninstall the variants which are not kept to reclaim space"`
+	Yes     bool     `arg:"-y" help:"do not ask for confirmation"`
 }
 
 type UpdateCmd struct {
-	Update bool `default:"true"`
+	Update    bool     `default:"true"`
+	Fonts     []string `arg:"positional" help:"list of space separated fonts to update, all outdated fonts by default"`
+	Yes       bool     `arg:"-y" help:"update all outdated fonts without picking them"`
+	ShowNotes bool     `arg:"--show-notes" help:"show the release notes of the versions being installed"`
+}
+
+type ChangelogCmd struct {
+	Font string `arg:"positional" help:"font to show the changes for, defaults to all installed fonts"`
+}
+
+type CheckCmd struct{}
+
+type InfoCmd struct {
+	Font string `arg:"positional,required" help:"font to show the details of"`
+}
+
+type PreviewCmd struct {
+	Font    string  `arg:"positional,required" help:"font to preview"`
+	Out     string  `arg:"-o,--out" help:"png file to write, <font>.png by default"`
+	Text    string  `arg:"-t,--text" help:"sample text, lines are separated by \\n"`
+	Size    float64 `arg:"-s,--size" default:"32" help:"font size in points"`
+	Variant string  `arg:"--variant" default:"default" help:"variant to preview: default, mono or propo"`
+}
+
+type CompareCmd struct {
+	Fonts    []string `arg:"positional,required" help:"list of space separated fonts to compare"`
+	Out      string   `arg:"-o,--out" default:"compare.png" help:"png or html file to write"`
+	Text     string   `arg:"-t,--text" help:"sample text, lines are separated by \\n"`
+	Size     float64  `arg:"-s,--size" default:"24" help:"font size in points"`
+	Variant  string   `arg:"--variant" default:"mono" help:"variant to compare: default, mono or propo"`
+	Download bool     `arg:"-d,--download" help:"download fonts which are neither installed nor cached"`
+}
+
+type GlyphSearchCmd struct {
+	Query string `arg:"positional,required" help:"name or codepoint of the glyph"`
+	Limit int    `arg:"-l" default:"20" help:"maximum number of glyphs to show"`
+}
+
+type GlyphBrowseCmd struct {
+	Query string `arg:"positional" help:"initial filter"`
+}
+
+type GlyphCmd struct {
+	Search *GlyphSearchCmd `arg:"subcommand:search" help:"find glyphs by name and print their codepoints and escape sequences"`
+	Browse *GlyphBrowseCmd `arg:"subcommand:browse" help:"browse the glyphs and copy them to the clipboard, the default"`
+}
+
+type CacheListCmd struct{}
+
+type CacheCleanCmd struct{}
+
+type CachePruneCmd struct {
+	OlderThan    string `arg:"--older-than" help:"remove archives older than this, e.g. 30d or 12h"`
+	MaxSize      string `arg:"--max-size" help:"remove the oldest archives until the cache is smaller than this, e.g. 500MB"`
+	NotInstalled bool   `arg:"--not-installed" help:"remove archives of versions which are not installed, the default without other options"`
+}
+
+type CacheCmd struct {
+	List  *CacheListCmd  `arg:"subcommand:list" help:"list cached archives"`
+	Clean *CacheCleanCmd `arg:"subcommand:clean" help:"remove all cached archives"`
+	Prune *CachePruneCmd `arg:"subcommand:prune" help:"remove cached archives by age, size and installed version"`
+}
+
+type ConfigGetCmd struct {
+	Key string `arg:"positional,required" help:"name of the setting"`
+}
+
+type ConfigSetCmd struct {
+	Key   string `arg:"positional,required" help:"name of the setting"`
+	Value string `arg:"positional,required" help:"new value, lists are comma separated"`
+}
+
+type ConfigListCmd struct{}
+
+type ConfigCmd struct {
+	Get  *ConfigGetCmd  `arg:"subcommand:get" help:"print the value of a setting"`
+	Set  *ConfigSetCmd  `arg:"subcommand:set" help:"change a setting in the config file"`
+	List *ConfigListCmd `arg:"subcommand:list" help:"list all settings and their values"`
 }
 
 type Args struct {
 	Install    *InstallCmd   `arg:"subcommand:install" help:"install fonts"`
 	Uninstall  *UninstallCmd `arg:"subcommand:uninstall" help:"uninstall fonts"`
+	Purge      *PurgeCmd     `arg:"subcommand:purge" help:"uninstall all fonts and remove cached archives and optionally the database"`
+	Cache      *CacheCmd     `arg:"subcommand:cache" help:"inspect and trim the cache of downloaded archives"`
 	List       *ListCmd      `arg:"subcommand:list" help:"list fonts"`
+	Du         *DuCmd        `arg:"subcommand:du" help:"show the disk usage of installed fonts"`
 	Update     *UpdateCmd    `arg:"subcommand:update" help:"update installed fonts"`
+	Check      *CheckCmd     `arg:"subcommand:check" help:"check for updates of installed fonts, exits with 100 when updates are available"`
+	Changelog  *ChangelogCmd `arg:"subcommand:changelog" help:"show release notes between the installed and available versions"`
+	Info       *InfoCmd      `arg:"subcommand:info" help:"show the details of a font"`
+	Preview    *PreviewCmd   `arg:"subcommand:preview" help:"render a preview of a font to a png image"`
+	Compare    *CompareCmd   `arg:"subcommand:compare" help:"render fonts side by side to a png or html sheet"`
+	Glyph      *GlyphCmd     `arg:"subcommand:glyph" help:"look up and browse Nerd Fonts glyphs"`
+	Config     *ConfigCmd    `arg:"subcommand:config" help:"show or change settings"`
 	KeepTars   bool          `arg:"-k" help:"Keep archives in the download location"`
+	DryRun     bool          `arg:"-n,--dry-run" help:"show what install, uninstall and update would do without changing anything"`
+	Channel    string        `arg:"-c" help:"release channel to install from, stable or prerelease"`
 	ForceCheck bool          `arg:"-f" help:"Force checking for updates"`
 }
 
 func (Args) Version() string {
 	return "WiNFerior v0.1.0"
 }
+
+// Http cache
+
+// HttpCache holds the validators of the last successful response for Url, they are sent
+// back as If-None-Match and If-Modified-Since to make repeated checks cheap
+type HttpCache struct {
+	Url          string
+	ETag         string
+	LastModified string
+}
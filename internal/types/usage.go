@@ -0,0 +1,20 @@
+package types
+
+// Disk usage
+
+// FontUsage is the space an installed font takes on disk, split up by variant. Files
+// which are not fonts, like licenses and readmes, only count towards Size.
+type FontUsage struct {
+	Name        string
+	Version     string
+	Files       int
+	Size        int64
+	ArchiveSize int64
+	Variants    map[string]int64
+	Unused      []string
+	UnusedSize  int64
+}
+
+func (u FontUsage) GetVariantSize(variant string) int64 {
+	return u.Variants[variant]
+}
@@ -0,0 +1,130 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Versions
+
+// Version is a semantic version as used by the Nerd Fonts release tags, e.g. v3.2.1 or
+// v2.2.0-RC. Missing minor and patch numbers are read as zero and build metadata is ignored.
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+func ParseVersion(s string) (Version, error) {
+	var v Version
+
+	tag := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	tag, _, _ = strings.Cut(tag, "+")
+	tag, v.Prerelease, _ = strings.Cut(tag, "-")
+
+	parts := strings.Split(tag, ".")
+	if tag == "" || len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+	numbers := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version %q", s)
+		}
+		*numbers[i] = n
+	}
+
+	return v, nil
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+func (v Version) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+// Compare returns -1, 0 or 1 when v is older, the same or newer than o, following the
+// semver precedence rules: a prerelease is older than its release and prerelease
+// identifiers are compared numerically when both are numbers
+func (v Version) Compare(o Version) int {
+	for _, c := range [][2]int{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if c[0] != c[1] {
+			return compareInts(c[0], c[1])
+		}
+	}
+
+	switch {
+	case v.Prerelease == o.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case o.Prerelease == "":
+		return -1
+	}
+
+	vIds := strings.Split(v.Prerelease, ".")
+	oIds := strings.Split(o.Prerelease, ".")
+	for i := 0; i < len(vIds) && i < len(oIds); i++ {
+		vn, vErr := strconv.Atoi(vIds[i])
+		on, oErr := strconv.Atoi(oIds[i])
+		switch {
+		case vErr == nil && oErr == nil:
+			if vn != on {
+				return compareInts(vn, on)
+			}
+		case vErr == nil:
+			return -1
+		case oErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(strings.ToLower(vIds[i]), strings.ToLower(oIds[i])); c != 0 {
+				return c
+			}
+		}
+	}
+
+	return compareInts(len(vIds), len(oIds))
+}
+
+func compareInts(a int, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// CompareVersions compares two version strings, versions which fail to parse are older
+// than any valid version
+func CompareVersions(a string, b string) int {
+	va, errA := ParseVersion(a)
+	vb, errB := ParseVersion(b)
+	switch {
+	case errA != nil && errB != nil:
+		return 0
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	default:
+		return va.Compare(vb)
+	}
+}
+
+// SortVersions sorts version strings from oldest to newest
+func SortVersions(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool { return CompareVersions(versions[i], versions[j]) < 0 })
+}
@@ -0,0 +1,92 @@
+package types
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		tag     string
+		want    Version
+		wantErr bool
+	}{
+		{tag: "v3.0.0", want: Version{Major: 3}},
+		{tag: "v3.2.1", want: Version{Major: 3, Minor: 2, Patch: 1}},
+		{tag: "v3.1.10", want: Version{Major: 3, Minor: 1, Patch: 10}},
+		{tag: "3.2.1", want: Version{Major: 3, Minor: 2, Patch: 1}},
+		{tag: " v3.2.1\n", want: Version{Major: 3, Minor: 2, Patch: 1}},
+		{tag: "v2.2.0-RC", want: Version{Major: 2, Minor: 2, Prerelease: "RC"}},
+		{tag: "v2.3.0-RC", want: Version{Major: 2, Minor: 3, Prerelease: "RC"}},
+		{tag: "v3.0.0-rc.2", want: Version{Major: 3, Prerelease: "rc.2"}},
+		{tag: "v2.1", want: Version{Major: 2, Minor: 1}},
+		{tag: "v3.2.1+build.5", want: Version{Major: 3, Minor: 2, Patch: 1}},
+		{tag: "", wantErr: true},
+		{tag: "v", wantErr: true},
+		{tag: "-", wantErr: true},
+		{tag: "latest", wantErr: true},
+		{tag: "v3.x.0", wantErr: true},
+		{tag: "v3.2.1.0", wantErr: true},
+		{tag: "v3..1", wantErr: true},
+		{tag: "v-1.0.0", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.tag)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseVersion(%q) = %v, want an error", tt.tag, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseVersion(%q) returned %v", tt.tag, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseVersion(%q) = %+v, want %+v", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{"v3.2.1", "v3.2.1", 0},
+		{"v3.2.1", "3.2.1", 0},
+		{"v3.1", "v3.1.0", 0},
+		{"v3.1.10", "v3.2.0", -1},
+		{"v3.1.10", "v3.1.9", 1},
+		{"v3.0.0", "v2.3.3", 1},
+		{"v2.3.3", "v3.0.0", -1},
+		{"v2.2.0-RC", "v2.2.0", -1},
+		{"v2.2.0", "v2.2.0-RC", 1},
+		{"v2.2.0-RC", "v2.1.0", 1},
+		{"v2.3.0-RC", "v2.2.2", 1},
+		{"v3.0.0-rc.2", "v3.0.0-rc.10", -1},
+		{"v3.0.0-rc", "v3.0.0-rc.1", -1},
+		{"v3.0.0-1", "v3.0.0-rc", -1},
+		{"v3.0.0-RC", "v3.0.0-rc", 0},
+		{"", "v3.0.0", -1},
+		{"v3.0.0", "", 1},
+		{"-", "v2.0.0", -1},
+		{"invalid", "v2.0.0", -1},
+		{"", "invalid", 0},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSortVersions(t *testing.T) {
+	versions := []string{"v3.2.0", "v2.2.0", "v3.1.10", "v2.2.0-RC", "v3.0.0", "v3.1.9", "v2.3.0-RC"}
+	want := []string{"v2.2.0-RC", "v2.2.0", "v2.3.0-RC", "v3.0.0", "v3.1.9", "v3.1.10", "v3.2.0"}
+	SortVersions(versions)
+	if !slices.Equal(versions, want) {
+		t.Errorf("SortVersions = %v, want %v", versions, want)
+	}
+}
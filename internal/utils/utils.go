@@ -1,8 +1,6 @@
 package utils
 
 import (
-	"regexp"
-	"strconv"
 	"strings"
 )
 
@@ -26,16 +24,6 @@ func Fold[T, U any](data []T, f func(T) U) []U {
 	return res
 }
 
-func StringToInt(version string) (int, error) {
-	re := regexp.MustCompile("[0-9]+")
-	versionCleaned := re.FindAllString(version, -1)
-	versionInt, err := strconv.Atoi(strings.Join(versionCleaned[:], ""))
-	if err != nil {
-		return 0, err
-	}
-	return versionInt, nil
-}
-
 func FontNameWithoutExtention(name string) string {
 	return strings.Split(name, ".")[0]
 }
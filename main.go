@@ -4,9 +4,10 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
-	"time"
+	"os"
 
 	"github.com/alexflint/go-arg"
+	"github.com/getnf/winferior/internal/config"
 	"github.com/getnf/winferior/internal/db"
 	"github.com/getnf/winferior/internal/handlers"
 	"github.com/getnf/winferior/internal/tui"
@@ -17,78 +18,225 @@ func main() {
 	var args types.Args
 	arg.MustParse(&args)
 
+	cfg, err := config.Load()
+	if args.Config != nil {
+		if err != nil {
+			fmt.Println(err)
+		}
+		err = handlers.HandleConfig(args.Config, cfg)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if args.KeepTars {
+		cfg.KeepTars = true
+	}
+	if args.Channel != "" {
+		cfg.Channel = args.Channel
+		err = cfg.Validate()
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	tui.Configure(cfg.GetTheme(), tui.NewKeymap(cfg.Keymap))
+
 	var database *sql.DB
 
-	paths := types.NewPaths()
-	downloadPath := paths.GetDownloadPath()
-	extractPath := paths.GetInstallPath()
+	paths := cfg.Paths()
+	source := cfg.Source()
+	opts := cfg.Options(paths)
+	opts.DryRun = args.DryRun
 	dbPath := paths.GetDbPath()
 	isAdmin := handlers.IsAdmin()
+	changesFonts := args.List == nil && args.Check == nil && args.Changelog == nil && args.Info == nil && args.Cache == nil && args.Glyph == nil && args.Preview == nil && args.Compare == nil && (args.Du == nil || args.Du.Reclaim) && !args.DryRun
 
-	if !isAdmin {
-		log.Fatalln("winferior need admin rights to install fonts, please run winferior as administrator")
+	if !isAdmin && changesFonts && opts.Scope == types.ScopeMachine {
+		log.Fatalln("winferior need admin rights to install fonts for all users, please run winferior as administrator or set the scope to user")
 	}
 
 	database = db.OpenDB(dbPath)
 
-	db.CreateLastCheckedTable(database)
-
-	lastChecked, _ := time.Parse(time.DateTime, db.GetLastChecked(database))
-	DaysSinceLastChecked := int(time.Since(lastChecked).Hours() / 24)
-
-	if db.TableIsEmpty(database, "lastChecked") || DaysSinceLastChecked > 5 || args.ForceCheck {
-		remoteData, err := handlers.GetData()
-		if err == nil {
-			handlers.SetupDB(database, remoteData)
+	db.CreateInstalledFontsTable(database)
+	channels := []types.Channel{opts.Channel}
+	for _, channel := range db.GetInstalledChannels(database) {
+		if channel != opts.Channel {
+			channels = append(channels, channel)
 		}
-		db.UpdateLastChecked(database)
 	}
 
-	var data types.NerdFonts
+	err = handlers.RefreshCatalog(database, source, channels, cfg.GetRefreshInterval(), args.ForceCheck || args.Check != nil)
+	if err != nil {
+		// the warning must not end up in json output
+		fmt.Fprintln(os.Stderr, "could not refresh the fonts catalog:", err)
+		if args.Check != nil {
+			os.Exit(1)
+		}
+	}
 
-	data.Version = db.GetVersion(database)
-	data.Fonts = db.GetAllFonts(database)
+	data := db.GetCatalog(database, opts.Channel)
 
 	switch {
 	case args.List != nil:
-		if !args.List.Installed {
-			handlers.ListFonts(handlers.FontsWithVersion(database, data.GetFonts(), data.GetVersion()), false)
-		} else {
-			handlers.ListFonts(handlers.FontsWithVersion(database, data.GetFonts(), data.GetVersion()), true)
+		fonts := handlers.FontsWithVersion(database, data.GetFonts(), data.GetVersion())
+		fonts = handlers.FontsWithMeta(database, fonts)
+		if args.List.Size {
+			fonts = handlers.FontsWithSize(fonts, opts)
+		}
+		err := handlers.ListFonts(fonts, args.List, handlers.FontAliases(database), cfg.Output)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	case args.Info != nil:
+		err := handlers.HandleInfo(args.Info, database, data, opts, cfg.Output, fontNameCorrector())
+		if err != nil {
+			log.Fatalln(err)
 		}
 	case args.Install != nil:
 		if len(args.Install.Fonts) == 0 {
-			err := tui.SelectFontsToInstall(data, database, downloadPath, extractPath, args.KeepTars)
+			err := tui.SelectFontsToInstall(data, database, source, opts)
 			if err != nil {
 				fmt.Println(err)
 			}
 		} else {
-			err := handlers.HandleInstall(args, database, data, downloadPath, extractPath)
+			err := handlers.HandleInstall(args, database, data, source, opts, fontNameCorrector())
 			if err != nil {
 				fmt.Println(err)
 			}
 		}
 	case args.Uninstall != nil:
-		if len(args.Uninstall.Fonts) == 0 {
-			err := tui.SelectFontsToUninstall(db.GetInstalledFonts(database), database, extractPath)
+		if args.Uninstall.All && !args.Uninstall.Yes && !opts.DryRun {
+			confirmOrExit("Uninstall all installed fonts?")
+		}
+		if len(args.Uninstall.Fonts) == 0 && !args.Uninstall.All {
+			err := tui.SelectFontsToUninstall(data, database, source, opts)
 			if err != nil {
 				fmt.Println(err)
 			}
 		} else {
-			err := handlers.HandleUninstall(args, database, data, extractPath)
+			err := handlers.HandleUninstall(args, database, data, opts, fontNameCorrector())
 			if err != nil {
 				fmt.Println(err)
 			}
 		}
+	case args.Purge != nil:
+		if !args.Purge.Yes && !opts.DryRun {
+			title := "Uninstall all fonts and delete the cached archives?"
+			if args.Purge.Db {
+				title = "Uninstall all fonts and delete the cached archives and the database?"
+			}
+			confirmOrExit(title)
+		}
+		err := handlers.HandlePurge(args.Purge, database, dbPath, opts)
+		if err != nil {
+			fmt.Println(err)
+		}
+	case args.Du != nil:
+		if args.Du.Reclaim && !args.Du.Yes && !opts.DryRun {
+			confirmOrExit("Uninstall the font variants which are not kept?")
+		}
+		err := handlers.HandleDu(args.Du, database, data, opts, fontNameCorrector())
+		if err != nil {
+			fmt.Println(err)
+		}
+	case args.Preview != nil:
+		err := handlers.HandlePreview(args.Preview, database, data, source, opts, fontNameCorrector())
+		if err != nil {
+			fmt.Println(err)
+		}
+	case args.Compare != nil:
+		err := handlers.HandleCompare(args.Compare, database, data, source, opts, fontNameCorrector())
+		if err != nil {
+			fmt.Println(err)
+		}
+	case args.Glyph != nil:
+		glyphs, err := handlers.LoadGlyphs(database, data, source)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if args.Glyph.Search != nil {
+			err = handlers.HandleGlyphSearch(args.Glyph.Search, glyphs, cfg.Output)
+		} else {
+			var query string
+			if args.Glyph.Browse != nil {
+				query = args.Glyph.Browse.Query
+			}
+			err = tui.BrowseGlyphs(glyphs, query, database, data, source, opts)
+		}
+		if err != nil {
+			fmt.Println(err)
+		}
+	case args.Cache != nil:
+		err := handlers.HandleCache(args.Cache, database, opts)
+		if err != nil {
+			fmt.Println(err)
+		}
+	case args.Check != nil:
+		if handlers.HandleCheck(database) {
+			os.Exit(100)
+		}
+	case args.Changelog != nil:
+		err := handlers.HandleChangelog(args.Changelog, database, data, source, fontNameCorrector())
+		if err != nil {
+			fmt.Println(err)
+		}
 	case args.Update != nil:
-		err := handlers.HandleUpdate(database, data, downloadPath, extractPath)
+		fonts, err := handlers.OutdatedFontsNamed(database, args.Update.Fonts, fontNameCorrector())
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		// a dry run shows the plan of every outdated font without asking
+		if len(args.Update.Fonts) == 0 && !args.Update.Yes && !opts.DryRun && len(fonts) > 0 && tui.IsInteractive() {
+			fonts, err = tui.SelectFontsToUpdate(fonts)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			if len(fonts) == 0 {
+				fmt.Println("No fonts selected")
+				return
+			}
+		}
+		err = handlers.HandleUpdate(database, source, opts, fonts, args.Update.ShowNotes)
 		if err != nil {
 			fmt.Println(err)
 		}
 	default:
-		err := tui.SelectFontsToInstall(data, database, downloadPath, extractPath, args.KeepTars)
+		err := tui.Dashboard(database, data, source, opts)
 		if err != nil {
 			fmt.Println(err)
 		}
 	}
 }
+
+// confirmOrExit asks the user to confirm a destructive command and exits unless they do,
+// without a terminal to ask on the command has to be confirmed with -y
+func confirmOrExit(title string) {
+	if !tui.IsInteractive() {
+		fmt.Fprintln(os.Stderr, title, "Pass -y to confirm without a terminal.")
+		os.Exit(1)
+	}
+	confirmed, err := tui.Confirm(title)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if !confirmed {
+		fmt.Println("aborted")
+		os.Exit(1)
+	}
+}
+
+// fontNameCorrector offers to correct unknown font names when there is a user to ask
+func fontNameCorrector() handlers.CorrectFunc {
+	if !tui.IsInteractive() {
+		return nil
+	}
+	return tui.CorrectFontName
+}